@@ -8,6 +8,8 @@ import (
 )
 
 type ArrayLiteralExpression struct {
+	Span
+
 	Token    token.Token
 	Elements []Expression
 }
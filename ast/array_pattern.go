@@ -0,0 +1,44 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+// ArrayPattern destructures the array into names like let [a, [b, c], ...rest] = array
+type ArrayPattern struct {
+	Span
+
+	Token    token.Token
+	Elements []Expression          // identifier, nested pattern or literal pattern of match expression
+	Rest     *IdentifierExpression // collects the remaining elements, the name is "_" when it is omitted like [a, ...]
+}
+
+func (a *ArrayPattern) expressionNode() {
+}
+
+// Implement methods for Node interface
+func (a *ArrayPattern) TokenLiteral() string {
+	return a.Token.Literal
+}
+
+func (a *ArrayPattern) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, element := range a.Elements {
+		elements = append(elements, element.String())
+	}
+
+	if a.Rest != nil {
+		elements = append(elements, restString(a.Rest))
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
@@ -7,6 +7,8 @@ import (
 )
 
 type AssignExpression struct {
+	Span
+
 	Token token.Token
 	Left  Expression
 	Value Expression
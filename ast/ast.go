@@ -1,9 +1,15 @@
 package ast
 
+import (
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
 // Node is the base node interface
 type Node interface {
 	TokenLiteral() string
 	String() string
+	StartPos() token.Position
+	EndPos() token.Position
 }
 
 // Expression node should be implement this interface
@@ -7,6 +7,8 @@ import (
 )
 
 type BlockStatement struct {
+	Span
+
 	Token      token.Token
 	Statements []Statement
 }
@@ -5,6 +5,8 @@ import (
 )
 
 type BooleanExpression struct {
+	Span
+
 	Token token.Token
 	Value bool
 }
@@ -7,6 +7,8 @@ import (
 )
 
 type BreakExpression struct {
+	Span
+
 	Token token.Token
 }
 
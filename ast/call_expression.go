@@ -8,6 +8,8 @@ import (
 )
 
 type CallExpression struct {
+	Span
+
 	Token     token.Token
 	Function  Expression
 	Arguments []Expression
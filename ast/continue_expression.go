@@ -7,6 +7,8 @@ import (
 )
 
 type ContinueExpression struct {
+	Span
+
 	Token token.Token
 }
 
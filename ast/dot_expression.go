@@ -7,6 +7,8 @@ import (
 )
 
 type DotExpression struct {
+	Span
+
 	Token token.Token
 	Left  Expression
 	Item  Expression
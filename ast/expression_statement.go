@@ -5,6 +5,8 @@ import (
 )
 
 type ExpressionStatement struct {
+	Span
+
 	Token      token.Token
 	Expression Expression
 }
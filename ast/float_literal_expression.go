@@ -5,6 +5,8 @@ import (
 )
 
 type FloatLiteralExpression struct {
+	Span
+
 	Token token.Token
 	Value float64
 }
@@ -7,8 +7,11 @@ import (
 )
 
 type ForEachArrayOrRangeExpression struct {
+	Span
+
 	Token    token.Token
 	Value    string
+	Pattern  Expression // destructuring pattern of each value like [key, value], the value is empty when it is set
 	Iterable Expression
 	Block    *BlockStatement
 }
@@ -24,8 +27,14 @@ func (f *ForEachArrayOrRangeExpression) TokenLiteral() string {
 func (f *ForEachArrayOrRangeExpression) String() string {
 	var out bytes.Buffer
 
-	out.WriteString("for")                     // for
-	out.WriteString(" " + f.Value + " ")       //	value
+	out.WriteString("for") // for
+
+	if f.Pattern != nil {
+		out.WriteString(" " + f.Pattern.String() + " ") // pattern
+	} else {
+		out.WriteString(" " + f.Value + " ") //	value
+	}
+
 	out.WriteString("in")                      // in
 	out.WriteString(" " + f.Iterable.String()) // 	{k: v, k: v}
 	out.WriteString(" { ")                     // {
@@ -7,6 +7,8 @@ import (
 )
 
 type ForEachHashExpression struct {
+	Span
+
 	Token    token.Token
 	Key      string
 	Value    string
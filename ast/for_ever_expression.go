@@ -7,6 +7,8 @@ import (
 )
 
 type ForEverExpression struct {
+	Span
+
 	Token token.Token
 	Block *BlockStatement
 }
@@ -8,8 +8,12 @@ import (
 )
 
 type FunctionLiteralExpression struct {
+	Span
+
 	Token      token.Token
 	Parameters []*IdentifierExpression
+	Defaults   map[string]Expression // default value of parameter, e.g. func(a, b = 2)
+	Rest       *IdentifierExpression // variadic parameter which collects the remaining arguments, e.g. func(a, ...rest)
 	Block      *BlockStatement
 }
 
@@ -24,11 +28,7 @@ func (f *FunctionLiteralExpression) TokenLiteral() string {
 func (f *FunctionLiteralExpression) String() string {
 	var out bytes.Buffer
 
-	parameters := []string{}
-
-	for _, parameter := range f.Parameters {
-		parameters = append(parameters, parameter.String())
-	}
+	parameters := ParameterStrings(f.Parameters, f.Defaults, f.Rest)
 
 	// Only for expression:
 	// let foo = func(params) { block }
@@ -42,3 +42,22 @@ func (f *FunctionLiteralExpression) String() string {
 
 	return out.String()
 }
+
+// ParameterStrings returns the parameters with its default value and the variadic parameter, e.g. [a, b = 2, ...rest]
+func ParameterStrings(parameters []*IdentifierExpression, defaults map[string]Expression, rest *IdentifierExpression) []string {
+	strs := []string{}
+
+	for _, parameter := range parameters {
+		if value, ok := defaults[parameter.Value]; ok {
+			strs = append(strs, parameter.String()+" = "+value.String())
+		} else {
+			strs = append(strs, parameter.String())
+		}
+	}
+
+	if rest != nil {
+		strs = append(strs, "..."+rest.String())
+	}
+
+	return strs
+}
@@ -8,6 +8,8 @@ import (
 )
 
 type FunctionStatement struct {
+	Span
+
 	Token    token.Token
 	Name     *IdentifierExpression
 	Function *FunctionLiteralExpression
@@ -24,10 +26,7 @@ func (f *FunctionStatement) TokenLiteral() string {
 func (f *FunctionStatement) String() string {
 	var out bytes.Buffer
 
-	parameters := []string{}
-	for _, parameter := range f.Function.Parameters {
-		parameters = append(parameters, parameter.String())
-	}
+	parameters := ParameterStrings(f.Function.Parameters, f.Function.Defaults, f.Function.Rest)
 
 	out.WriteString("func ")                        // func
 	out.WriteString(f.Name.String())                // name
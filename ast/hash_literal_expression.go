@@ -8,6 +8,8 @@ import (
 )
 
 type HashLiteralExpression struct {
+	Span
+
 	Token token.Token
 	Order []Expression
 	Pairs map[Expression]Expression
@@ -0,0 +1,63 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+// HashPatternPair takes the value of key into the identifier or nested pattern, e.g. name or age: years
+type HashPatternPair struct {
+	Key   *IdentifierExpression
+	Value Expression
+}
+
+// HashPattern destructures the hash into names like let {name, age: years, ...rest} = hash
+type HashPattern struct {
+	Span
+
+	Token token.Token
+	Pairs []*HashPatternPair
+	Rest  *IdentifierExpression // collects the remaining pairs, the name is "_" when it is omitted like {a, ...}
+}
+
+func (h *HashPattern) expressionNode() {
+}
+
+// Implement methods for Node interface
+func (h *HashPattern) TokenLiteral() string {
+	return h.Token.Literal
+}
+
+func (h *HashPattern) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range h.Pairs {
+		if identifier, ok := pair.Value.(*IdentifierExpression); ok && identifier.Value == pair.Key.Value {
+			pairs = append(pairs, pair.Key.String())
+		} else {
+			pairs = append(pairs, pair.Key.String()+": "+pair.Value.String())
+		}
+	}
+
+	if h.Rest != nil {
+		pairs = append(pairs, restString(h.Rest))
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// The omitted rest name is "_", so show it as "..." only
+func restString(rest *IdentifierExpression) string {
+	if rest.Value == "_" {
+		return "..."
+	}
+
+	return "..." + rest.String()
+}
@@ -5,6 +5,8 @@ import (
 )
 
 type IdentifierExpression struct {
+	Span
+
 	Token token.Token
 	Value string
 }
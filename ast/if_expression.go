@@ -12,6 +12,8 @@ type IfScene struct {
 }
 
 type IfExpression struct {
+	Span
+
 	Token       token.Token
 	Scenes      []*IfScene
 	Alternative *BlockStatement
@@ -0,0 +1,40 @@
+package ast
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+type ImportStatement struct {
+	Span
+
+	Token token.Token
+	Path  string
+	Alias *IdentifierExpression
+}
+
+func (i *ImportStatement) statementNode() {
+}
+
+// Implement methods for Node interface
+func (i *ImportStatement) TokenLiteral() string {
+	return i.Token.Literal
+}
+
+func (i *ImportStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(i.TokenLiteral() + " ") // import
+	out.WriteString(strconv.Quote(i.Path))  // "path"
+
+	if i.Alias != nil {
+		out.WriteString(" as ")           // as
+		out.WriteString(i.Alias.String()) // alias
+	}
+
+	out.WriteString(";") // ;
+
+	return out.String()
+}
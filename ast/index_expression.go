@@ -7,6 +7,8 @@ import (
 )
 
 type IndexExpression struct {
+	Span
+
 	Token token.Token
 	Left  Expression
 	Index Expression
@@ -7,6 +7,8 @@ import (
 )
 
 type InfixExpression struct {
+	Span
+
 	Token    token.Token
 	Left     Expression
 	Operator string
@@ -5,6 +5,8 @@ import (
 )
 
 type IntegerLiteralExpression struct {
+	Span
+
 	Token token.Token
 	Value int64
 }
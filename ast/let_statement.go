@@ -7,9 +7,12 @@ import (
 )
 
 type LetStatement struct {
-	Token token.Token
-	Name  *IdentifierExpression
-	Value Expression
+	Span
+
+	Token   token.Token
+	Name    *IdentifierExpression
+	Pattern Expression // destructuring pattern like [a, b] or {name}, the name is nil when it is set
+	Value   Expression
 }
 
 func (l *LetStatement) statementNode() {
@@ -24,8 +27,14 @@ func (l *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(l.TokenLiteral() + " ") // let
-	out.WriteString(l.Name.String())        // variable
-	out.WriteString(" = ")                  // =
+
+	if l.Pattern != nil {
+		out.WriteString(l.Pattern.String()) // pattern
+	} else {
+		out.WriteString(l.Name.String()) // variable
+	}
+
+	out.WriteString(" = ") // =
 
 	if l.Value != nil {
 		out.WriteString(l.Value.String()) // Value
@@ -0,0 +1,65 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+// MatchArm runs the block when the pattern matched and the guard is truthy, e.g. n if n > 10 => n
+type MatchArm struct {
+	Pattern Expression
+	Guard   Expression // nil when there is no guard
+	Block   *BlockStatement
+}
+
+func (m *MatchArm) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(m.Pattern.String())
+
+	if m.Guard != nil {
+		out.WriteString(" if ")
+		out.WriteString(m.Guard.String())
+	}
+
+	out.WriteString(" => { ")
+	out.WriteString(m.Block.String())
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+type MatchExpression struct {
+	Span
+
+	Token   token.Token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (m *MatchExpression) expressionNode() {
+}
+
+// Implement methods for Node interface
+func (m *MatchExpression) TokenLiteral() string {
+	return m.Token.Literal
+}
+
+func (m *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, arm := range m.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match ")                 // match
+	out.WriteString(m.Subject.String())       // subject
+	out.WriteString(" { ")                    // {
+	out.WriteString(strings.Join(arms, ", ")) // 	pattern => { block }, ...
+	out.WriteString(" }")                     // }
+
+	return out.String()
+}
@@ -0,0 +1,34 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+// NamedArgumentExpression is the argument passed by parameter name like f(b: 3, a: 1)
+type NamedArgumentExpression struct {
+	Span
+
+	Token token.Token
+	Name  *IdentifierExpression
+	Value Expression
+}
+
+func (n *NamedArgumentExpression) expressionNode() {
+}
+
+// Implement methods for Node interface
+func (n *NamedArgumentExpression) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+func (n *NamedArgumentExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(n.Name.String())  // name
+	out.WriteString(": ")             // :
+	out.WriteString(n.Value.String()) // value
+
+	return out.String()
+}
@@ -5,6 +5,8 @@ import (
 )
 
 type NilLiteralExpression struct {
+	Span
+
 	Token token.Token
 }
 
@@ -0,0 +1,37 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+// OrPattern matches when any of the patterns matched, e.g. 1 | 2 | 3
+type OrPattern struct {
+	Span
+
+	Token    token.Token
+	Patterns []Expression
+}
+
+func (o *OrPattern) expressionNode() {
+}
+
+// Implement methods for Node interface
+func (o *OrPattern) TokenLiteral() string {
+	return o.Token.Literal
+}
+
+func (o *OrPattern) String() string {
+	var out bytes.Buffer
+
+	patterns := []string{}
+	for _, pattern := range o.Patterns {
+		patterns = append(patterns, pattern.String())
+	}
+
+	out.WriteString(strings.Join(patterns, " | "))
+
+	return out.String()
+}
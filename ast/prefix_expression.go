@@ -7,6 +7,8 @@ import (
 )
 
 type PrefixExpression struct {
+	Span
+
 	Token    token.Token
 	Operator string
 	Right    Expression
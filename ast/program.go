@@ -6,6 +6,8 @@ import (
 
 // Program is the root node of each AST in parser produces
 type Program struct {
+	Span
+
 	Statements []Statement
 }
 
@@ -7,6 +7,8 @@ import (
 )
 
 type RangeExpression struct {
+	Span
+
 	Token token.Token
 	Start Expression
 	End   Expression
@@ -7,6 +7,8 @@ import (
 )
 
 type ReturnStatement struct {
+	Span
+
 	Token       token.Token
 	ReturnValue Expression
 }
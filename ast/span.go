@@ -0,0 +1,27 @@
+package ast
+
+import (
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+// Span is the source range of node, it will be embedded into each node
+type Span struct {
+	start token.Position
+	end   token.Position // the position after the last character of node
+}
+
+// StartPos returns the start position of node
+func (s *Span) StartPos() token.Position {
+	return s.start
+}
+
+// EndPos returns the end position of node
+func (s *Span) EndPos() token.Position {
+	return s.end
+}
+
+// SetSpan sets the start and end position of node
+func (s *Span) SetSpan(start token.Position, end token.Position) {
+	s.start = start
+	s.end = end
+}
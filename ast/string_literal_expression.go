@@ -5,6 +5,8 @@ import (
 )
 
 type StringLiteralExpression struct {
+	Span
+
 	Token token.Token
 	Value string
 }
@@ -0,0 +1,49 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+// StructStatement declares the struct with fields and methods like struct Point { x, y = 0, func length() { ... } },
+// the method can access the instance by self
+type StructStatement struct {
+	Span
+
+	Token    token.Token
+	Name     *IdentifierExpression
+	Fields   []*IdentifierExpression
+	Defaults map[string]Expression // default value of field, e.g. y = 0
+	Methods  []*FunctionStatement
+}
+
+func (s *StructStatement) statementNode() {
+}
+
+// Implement methods for Node interface
+func (s *StructStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+func (s *StructStatement) String() string {
+	var out bytes.Buffer
+
+	members := ParameterStrings(s.Fields, s.Defaults, nil)
+	for _, method := range s.Methods {
+		members = append(members, method.String())
+	}
+
+	out.WriteString("struct ")       // struct
+	out.WriteString(s.Name.String()) // name
+	out.WriteString(" {")            // {
+
+	if len(members) > 0 {
+		out.WriteString(" " + strings.Join(members, ", ") + " ") // 	field, field = value, func method() { ... }
+	}
+
+	out.WriteString("}") // }
+
+	return out.String()
+}
@@ -0,0 +1,44 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+// TemplateLiteralExpression is the string with interpolations like "hello ${name}",
+// the parts are the string literals and expressions which will be concatenated
+type TemplateLiteralExpression struct {
+	Span
+
+	Token token.Token
+	Parts []Expression
+}
+
+func (t *TemplateLiteralExpression) expressionNode() {
+}
+
+// Implement methods for Node interface
+func (t *TemplateLiteralExpression) TokenLiteral() string {
+	return t.Token.Literal
+}
+
+func (t *TemplateLiteralExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("\"")
+
+	for _, part := range t.Parts {
+		if text, ok := part.(*StringLiteralExpression); ok {
+			out.WriteString(text.Value)
+		} else {
+			out.WriteString("${")
+			out.WriteString(part.String())
+			out.WriteString("}")
+		}
+	}
+
+	out.WriteString("\"")
+
+	return out.String()
+}
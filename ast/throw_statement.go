@@ -0,0 +1,36 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+type ThrowStatement struct {
+	Span
+
+	Token token.Token
+	Value Expression
+}
+
+func (t *ThrowStatement) statementNode() {
+}
+
+// Implement methods for Node interface
+func (t *ThrowStatement) TokenLiteral() string {
+	return t.Token.Literal
+}
+
+func (t *ThrowStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(t.TokenLiteral() + " ") // throw
+
+	if t.Value != nil {
+		out.WriteString(t.Value.String()) // value
+	}
+
+	out.WriteString(";") // ;
+
+	return out.String()
+}
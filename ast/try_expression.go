@@ -0,0 +1,57 @@
+package ast
+
+import (
+	"bytes"
+
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+type TryExpression struct {
+	Span
+
+	Token      token.Token
+	Block      *BlockStatement
+	Catch      *IdentifierExpression // the caught error name, it can be nil like: catch { ... }
+	CatchBlock *BlockStatement
+	Finally    *BlockStatement
+}
+
+func (t *TryExpression) expressionNode() {
+}
+
+// Implement methods for Node interface
+func (t *TryExpression) TokenLiteral() string {
+	return t.Token.Literal
+}
+
+func (t *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try")
+	out.WriteString(" { ")
+	out.WriteString(t.Block.String())
+	out.WriteString(" }")
+
+	if t.CatchBlock != nil {
+		out.WriteString(" catch")
+
+		if t.Catch != nil {
+			out.WriteString(" (")
+			out.WriteString(t.Catch.String())
+			out.WriteString(")")
+		}
+
+		out.WriteString(" { ")
+		out.WriteString(t.CatchBlock.String())
+		out.WriteString(" }")
+	}
+
+	if t.Finally != nil {
+		out.WriteString(" finally")
+		out.WriteString(" { ")
+		out.WriteString(t.Finally.String())
+		out.WriteString(" }")
+	}
+
+	return out.String()
+}
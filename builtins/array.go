@@ -0,0 +1,103 @@
+package builtins
+
+import (
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// Push function: push(array, value1, value2, ...), appends the values to the array and returns the array
+func Push(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("push", arguments, 1, -1); err != nil {
+		return err
+	}
+
+	array, ok := arguments[0].(*object.Array)
+	if ok == false {
+		return argumentError("push", 1, object.ARRAY_OBJECT, arguments[0])
+	}
+
+	if err := env.Allocate(int64(len(arguments) - 1)); err != nil {
+		return err
+	}
+
+	array.Elements = append(array.Elements, arguments[1:]...)
+
+	return array
+}
+
+// Pop function: pop(array), removes the last element of array and returns it, nil when the array is empty
+func Pop(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("pop", arguments, 1, 1); err != nil {
+		return err
+	}
+
+	array, ok := arguments[0].(*object.Array)
+	if ok == false {
+		return argumentError("pop", 1, object.ARRAY_OBJECT, arguments[0])
+	}
+
+	length := len(array.Elements)
+	if length == 0 {
+		return NIL
+	}
+
+	element := array.Elements[length-1]
+	array.Elements = array.Elements[:length-1]
+
+	return element
+}
+
+// Shift function: shift(array), removes the first element of array and returns it, nil when the array is empty
+func Shift(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("shift", arguments, 1, 1); err != nil {
+		return err
+	}
+
+	array, ok := arguments[0].(*object.Array)
+	if ok == false {
+		return argumentError("shift", 1, object.ARRAY_OBJECT, arguments[0])
+	}
+
+	if len(array.Elements) == 0 {
+		return NIL
+	}
+
+	element := array.Elements[0]
+	array.Elements = array.Elements[1:]
+
+	return element
+}
+
+// Insert function: insert(array, index, value), inserts the value before the index and returns the array
+func Insert(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("insert", arguments, 3, 3); err != nil {
+		return err
+	}
+
+	array, ok := arguments[0].(*object.Array)
+	if ok == false {
+		return argumentError("insert", 1, object.ARRAY_OBJECT, arguments[0])
+	}
+
+	index, ok := arguments[1].(*object.Integer)
+	if ok == false {
+		return argumentError("insert", 2, object.INTEGER_OBJECT, arguments[1])
+	}
+
+	length := int64(len(array.Elements))
+	if index.Value < 0 || index.Value > length {
+		return newError("insert index out of range, Got: %d, Expected: 0 to %d", index.Value, length)
+	}
+
+	if err := env.Allocate(1); err != nil {
+		return err
+	}
+
+	elements := make([]object.Object, 0, length+1)
+	elements = append(elements, array.Elements[:index.Value]...)
+	elements = append(elements, arguments[2])
+	elements = append(elements, array.Elements[index.Value:]...)
+
+	array.Elements = elements
+
+	return array
+}
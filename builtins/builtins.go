@@ -5,14 +5,85 @@ import (
 )
 
 var (
-	NIL = &object.Nil{}
+	NIL   = &object.Nil{}
+	TRUE  = &object.Boolean{Value: true}
+	FALSE = &object.Boolean{Value: false}
 )
 
-// BuiltIns function list
+// BuiltIns function list, it is shared by all programs so it must not be modified,
+// register the built-in function to the environment of program by SetBuiltIn instead
 var BuiltIns = map[string]*object.BuiltIn{
-	"print":   &object.BuiltIn{Function: Print},
-	"println": &object.BuiltIn{Function: Println},
+	"print":   &object.BuiltIn{Function: Print, Capability: object.IO_CAPABILITY},
+	"println": &object.BuiltIn{Function: Println, Capability: object.IO_CAPABILITY},
+	"exit":    &object.BuiltIn{Function: Exit},
+	"env":     &object.BuiltIn{Function: Env, Capability: object.ENV_CAPABILITY},
+	"setenv":  &object.BuiltIn{Function: SetEnv, Capability: object.ENV_CAPABILITY},
+
+	// collection
+	"len":      &object.BuiltIn{Function: Len},
+	"push":     &object.BuiltIn{Function: Push},
+	"pop":      &object.BuiltIn{Function: Pop},
+	"shift":    &object.BuiltIn{Function: Shift},
+	"insert":   &object.BuiltIn{Function: Insert},
+	"keys":     &object.BuiltIn{Function: Keys},
+	"values":   &object.BuiltIn{Function: Values},
+	"delete":   &object.BuiltIn{Function: Delete},
+	"contains": &object.BuiltIn{Function: Contains},
+	"index_of": &object.BuiltIn{Function: IndexOf},
+	"reverse":  &object.BuiltIn{Function: Reverse},
+	"slice":    &object.BuiltIn{Function: Slice},
+	"type":     &object.BuiltIn{Function: Type},
+
+	// string
+	"split":       &object.BuiltIn{Function: Split},
+	"join":        &object.BuiltIn{Function: Join},
+	"replace":     &object.BuiltIn{Function: Replace},
+	"trim":        &object.BuiltIn{Function: Trim},
+	"trim_left":   &object.BuiltIn{Function: TrimLeft},
+	"trim_right":  &object.BuiltIn{Function: TrimRight},
+	"trim_prefix": &object.BuiltIn{Function: TrimPrefix},
+	"trim_suffix": &object.BuiltIn{Function: TrimSuffix},
+	"upper":       &object.BuiltIn{Function: Upper},
+	"lower":       &object.BuiltIn{Function: Lower},
+	"starts_with": &object.BuiltIn{Function: StartsWith},
+	"ends_with":   &object.BuiltIn{Function: EndsWith},
+	"repeat":      &object.BuiltIn{Function: Repeat},
+	"pad_left":    &object.BuiltIn{Function: PadLeft},
+	"pad_right":   &object.BuiltIn{Function: PadRight},
+	"chars":       &object.BuiltIn{Function: Chars},
+	"format":      &object.BuiltIn{Function: Format},
+
+	// conversion
+	"to_int":    &object.BuiltIn{Function: ToInt},
+	"to_float":  &object.BuiltIn{Function: ToFloat},
+	"to_string": &object.BuiltIn{Function: ToString},
+
+	// higher-order
+	"map":      &object.BuiltIn{Function: Map},
+	"filter":   &object.BuiltIn{Function: Filter},
+	"reduce":   &object.BuiltIn{Function: Reduce},
+	"each":     &object.BuiltIn{Function: Each},
+	"any":      &object.BuiltIn{Function: Any},
+	"all":      &object.BuiltIn{Function: All},
+	"find":     &object.BuiltIn{Function: Find},
+	"zip":      &object.BuiltIn{Function: Zip},
+	"flatten":  &object.BuiltIn{Function: Flatten},
+	"group_by": &object.BuiltIn{Function: GroupBy},
+	"sort":     &object.BuiltIn{Function: Sort},
 
 	// alias
-	"echo": &object.BuiltIn{Function: Print},
+	"echo":    &object.BuiltIn{Function: Print, Capability: object.IO_CAPABILITY},
+	"sprintf": &object.BuiltIn{Function: Format},
+}
+
+func nativeBoolToBooleanObject(value bool) *object.Boolean {
+	if value == true {
+		return TRUE
+	}
+
+	return FALSE
+}
+
+func newError(format string, values ...interface{}) *object.Error {
+	return object.NewError(object.RUNTIME_ERROR, format, values...)
 }
@@ -0,0 +1,177 @@
+package builtins
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// Len function: len(string), len(array), len(hash), the length of string is the number of characters
+func Len(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("len", arguments, 1, 1); err != nil {
+		return err
+	}
+
+	switch argument := arguments[0].(type) {
+	case *object.String:
+		return &object.Integer{Value: int64(utf8.RuneCountInString(argument.Value))}
+	case *object.Array:
+		return &object.Integer{Value: int64(len(argument.Elements))}
+	case *object.Hash:
+		return &object.Integer{Value: int64(len(argument.Pairs))}
+	default:
+		return argumentError("len", 1, "STRING_OBJECT, ARRAY_OBJECT or HASH_OBJECT", argument)
+	}
+}
+
+// Contains function: contains(string, substring), contains(array, value), contains(hash, key)
+func Contains(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("contains", arguments, 2, 2); err != nil {
+		return err
+	}
+
+	switch collection := arguments[0].(type) {
+	case *object.String:
+		substring, ok := arguments[1].(*object.String)
+		if ok == false {
+			return argumentError("contains", 2, object.STRING_OBJECT, arguments[1])
+		}
+
+		return nativeBoolToBooleanObject(strings.Contains(collection.Value, substring.Value))
+	case *object.Array:
+		return nativeBoolToBooleanObject(indexOf(collection, arguments[1]) >= 0)
+	case *object.Hash:
+		key, err := hashKey(arguments[1])
+		if err != nil {
+			return err
+		}
+
+		_, ok := collection.Pairs[key]
+
+		return nativeBoolToBooleanObject(ok)
+	default:
+		return argumentError("contains", 1, "STRING_OBJECT, ARRAY_OBJECT or HASH_OBJECT", collection)
+	}
+}
+
+// IndexOf function: index_of(string, substring), index_of(array, value), returns -1 when it is not found,
+// the index of string is counted by characters
+func IndexOf(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("index_of", arguments, 2, 2); err != nil {
+		return err
+	}
+
+	switch collection := arguments[0].(type) {
+	case *object.String:
+		substring, ok := arguments[1].(*object.String)
+		if ok == false {
+			return argumentError("index_of", 2, object.STRING_OBJECT, arguments[1])
+		}
+
+		index := strings.Index(collection.Value, substring.Value)
+		if index > 0 {
+			index = utf8.RuneCountInString(collection.Value[:index])
+		}
+
+		return &object.Integer{Value: int64(index)}
+	case *object.Array:
+		return &object.Integer{Value: int64(indexOf(collection, arguments[1]))}
+	default:
+		return argumentError("index_of", 1, "STRING_OBJECT or ARRAY_OBJECT", collection)
+	}
+}
+
+// Reverse function: reverse(string), reverse(array), returns the new reversed string or array
+func Reverse(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("reverse", arguments, 1, 1); err != nil {
+		return err
+	}
+
+	switch collection := arguments[0].(type) {
+	case *object.String:
+		chars := []rune(collection.Value)
+
+		for left, right := 0, len(chars)-1; left < right; left, right = left+1, right-1 {
+			chars[left], chars[right] = chars[right], chars[left]
+		}
+
+		return &object.String{Value: string(chars)}
+	case *object.Array:
+		length := len(collection.Elements)
+
+		if err := env.Allocate(int64(length)); err != nil {
+			return err
+		}
+
+		elements := make([]object.Object, length)
+
+		for index, element := range collection.Elements {
+			elements[length-index-1] = element
+		}
+
+		return &object.Array{Elements: elements}
+	default:
+		return argumentError("reverse", 1, "STRING_OBJECT or ARRAY_OBJECT", collection)
+	}
+}
+
+// Slice function: slice(string, start), slice(array, start, end), returns the new string or array in the range,
+// the negative index counts from the end
+func Slice(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("slice", arguments, 2, 3); err != nil {
+		return err
+	}
+
+	start, ok := arguments[1].(*object.Integer)
+	if ok == false {
+		return argumentError("slice", 2, object.INTEGER_OBJECT, arguments[1])
+	}
+
+	end := &object.Integer{Value: int64(^uint64(0) >> 1)}
+	if len(arguments) == 3 {
+		if end, ok = arguments[2].(*object.Integer); ok == false {
+			return argumentError("slice", 3, object.INTEGER_OBJECT, arguments[2])
+		}
+	}
+
+	switch collection := arguments[0].(type) {
+	case *object.String:
+		chars := []rune(collection.Value)
+		from, to := sliceRange(start.Value, end.Value, len(chars))
+
+		return &object.String{Value: string(chars[from:to])}
+	case *object.Array:
+		from, to := sliceRange(start.Value, end.Value, len(collection.Elements))
+
+		if err := env.Allocate(int64(to - from)); err != nil {
+			return err
+		}
+
+		elements := make([]object.Object, to-from)
+		copy(elements, collection.Elements[from:to])
+
+		return &object.Array{Elements: elements}
+	default:
+		return argumentError("slice", 1, "STRING_OBJECT or ARRAY_OBJECT", collection)
+	}
+}
+
+// Type function: type(value), returns the type name of value, e.g. INTEGER_OBJECT
+func Type(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("type", arguments, 1, 1); err != nil {
+		return err
+	}
+
+	return &object.String{Value: string(arguments[0].Type())}
+}
+
+func indexOf(array *object.Array, value object.Object) int {
+	for index, element := range array.Elements {
+		if equals(element, value) == true {
+			return index
+		}
+	}
+
+	return -1
+}
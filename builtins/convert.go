@@ -0,0 +1,78 @@
+package builtins
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// ToInt function: to_int(value), converts the string, float or boolean to integer, the float is truncated
+func ToInt(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("to_int", arguments, 1, 1); err != nil {
+		return err
+	}
+
+	switch argument := arguments[0].(type) {
+	case *object.Integer:
+		return argument
+	case *object.Float:
+		if math.IsNaN(argument.Value) == true || argument.Value >= math.MaxInt64 || argument.Value < math.MinInt64 {
+			return newError("cannot convert %s to INTEGER_OBJECT, the value is overflow", argument.Inspect())
+		}
+
+		return &object.Integer{Value: int64(argument.Value)}
+	case *object.Boolean:
+		if argument.Value == true {
+			return &object.Integer{Value: 1}
+		}
+
+		return &object.Integer{Value: 0}
+	case *object.String:
+		value, err := strconv.ParseInt(strings.TrimSpace(argument.Value), 10, 64)
+		if err != nil {
+			return newError("cannot convert %q to INTEGER_OBJECT", argument.Value)
+		}
+
+		return &object.Integer{Value: value}
+	default:
+		return argumentError("to_int", 1, "STRING_OBJECT, FLOAT_OBJECT or BOOLEAN_OBJECT", argument)
+	}
+}
+
+// ToFloat function: to_float(value), converts the string or integer to float
+func ToFloat(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("to_float", arguments, 1, 1); err != nil {
+		return err
+	}
+
+	switch argument := arguments[0].(type) {
+	case *object.Float:
+		return argument
+	case *object.Integer:
+		return &object.Float{Value: float64(argument.Value)}
+	case *object.String:
+		value, err := strconv.ParseFloat(strings.TrimSpace(argument.Value), 64)
+		if err != nil {
+			return newError("cannot convert %q to FLOAT_OBJECT", argument.Value)
+		}
+
+		return &object.Float{Value: value}
+	default:
+		return argumentError("to_float", 1, "STRING_OBJECT or INTEGER_OBJECT", argument)
+	}
+}
+
+// ToString function: to_string(value), returns the string like print function
+func ToString(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("to_string", arguments, 1, 1); err != nil {
+		return err
+	}
+
+	if str, ok := arguments[0].(*object.String); ok {
+		return str
+	}
+
+	return &object.String{Value: arguments[0].Inspect()}
+}
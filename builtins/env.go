@@ -0,0 +1,83 @@
+package builtins
+
+import (
+	"os"
+	"strings"
+
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// Env function: env(), env(name)
+func Env(env *object.Environment, arguments ...object.Object) object.Object {
+	if len(arguments) == 0 {
+		return environmentHash()
+	}
+
+	if len(arguments) > 1 {
+		return newError("wrong number of arguments for env, Got: %d, Expected: 0 or 1", len(arguments))
+	}
+
+	name, ok := arguments[0].(*object.String)
+	if ok == false {
+		return newError("env name must be STRING_OBJECT, Got: %s", arguments[0].Type())
+	}
+
+	value, ok := os.LookupEnv(name.Value)
+	if ok == false {
+		return NIL
+	}
+
+	return &object.String{Value: value}
+}
+
+// SetEnv function: setenv(name, value)
+func SetEnv(env *object.Environment, arguments ...object.Object) object.Object {
+	if len(arguments) != 2 {
+		return newError("wrong number of arguments for setenv, Got: %d, Expected: 2", len(arguments))
+	}
+
+	name, ok := arguments[0].(*object.String)
+	if ok == false {
+		return newError("env name must be STRING_OBJECT, Got: %s", arguments[0].Type())
+	}
+
+	value, ok := arguments[1].(*object.String)
+	if ok == false {
+		return newError("env value must be STRING_OBJECT, Got: %s", arguments[1].Type())
+	}
+
+	if err := os.Setenv(name.Value, value.Value); err != nil {
+		return newError("cannot set env %s: %s", name.Value, err)
+	}
+
+	return NIL
+}
+
+// Convert the process environment to hash like {"HOME": "/root", ...}
+func environmentHash() *object.Hash {
+	hash := &object.Hash{
+		Order: []object.HashKey{},
+		Pairs: make(map[object.HashKey]object.HashPair),
+	}
+
+	for _, variable := range os.Environ() {
+		pair := strings.SplitN(variable, "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+
+		key := &object.String{Value: pair[0]}
+		hashKey := key.HashKey()
+
+		if _, ok := hash.Pairs[hashKey]; ok == false {
+			hash.Order = append(hash.Order, hashKey)
+		}
+
+		hash.Pairs[hashKey] = object.HashPair{
+			Key:   key,
+			Value: &object.String{Value: pair[1]},
+		}
+	}
+
+	return hash
+}
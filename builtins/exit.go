@@ -0,0 +1,28 @@
+package builtins
+
+import (
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// Exit function: exit(), exit(code)
+func Exit(env *object.Environment, arguments ...object.Object) object.Object {
+	if len(arguments) == 0 {
+		return object.NewExitError(0)
+	}
+
+	if len(arguments) > 1 {
+		return newError("wrong number of arguments for exit, Got: %d, Expected: 0 or 1", len(arguments))
+	}
+
+	code, ok := arguments[0].(*object.Integer)
+	if ok == false {
+		return newError("exit code must be INTEGER_OBJECT, Got: %s", arguments[0].Type())
+	}
+
+	// The exit status of process is 0 to 255 only, e.g. 300 will become 44
+	if code.Value < 0 || code.Value > 255 {
+		return newError("exit code out of range, Got: %d, Expected: 0 to 255", code.Value)
+	}
+
+	return object.NewExitError(code.Value)
+}
@@ -0,0 +1,428 @@
+package builtins
+
+import (
+	"sort"
+
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// Map function: map(array, func(element) { ... }), returns the new array of results
+func Map(env *object.Environment, arguments ...object.Object) object.Object {
+	array, function, err := arrayAndFunction("map", arguments)
+	if err != nil {
+		return err
+	}
+
+	if err := env.Allocate(int64(len(array.Elements))); err != nil {
+		return err
+	}
+
+	elements := make([]object.Object, len(array.Elements))
+
+	for index, element := range array.Elements {
+		result := env.Call(function, element)
+		if isError(result) == true {
+			return result
+		}
+
+		elements[index] = result
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// Filter function: filter(array, func(element) { ... }), returns the new array of elements which the result is truthy
+func Filter(env *object.Environment, arguments ...object.Object) object.Object {
+	array, function, err := arrayAndFunction("filter", arguments)
+	if err != nil {
+		return err
+	}
+
+	elements := []object.Object{}
+
+	for _, element := range array.Elements {
+		result := env.Call(function, element)
+		if isError(result) == true {
+			return result
+		}
+
+		if isTruthy(result) == true {
+			elements = append(elements, element)
+		}
+	}
+
+	if err := env.Allocate(int64(len(elements))); err != nil {
+		return err
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// Reduce function: reduce(array, func(accumulator, element) { ... }, initial),
+// the first element is the initial value when it is not provided
+func Reduce(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("reduce", arguments, 2, 3); err != nil {
+		return err
+	}
+
+	array, function, err := arrayAndFunction("reduce", arguments[:2])
+	if err != nil {
+		return err
+	}
+
+	elements := array.Elements
+
+	var accumulator object.Object
+	if len(arguments) == 3 {
+		accumulator = arguments[2]
+	} else {
+		if len(elements) == 0 {
+			return newError("reduce of empty array with no initial value")
+		}
+
+		accumulator, elements = elements[0], elements[1:]
+	}
+
+	for _, element := range elements {
+		accumulator = env.Call(function, accumulator, element)
+		if isError(accumulator) == true {
+			return accumulator
+		}
+	}
+
+	return accumulator
+}
+
+// Each function: each(array, func(element) { ... }), calls the function for each element and returns nil
+func Each(env *object.Environment, arguments ...object.Object) object.Object {
+	array, function, err := arrayAndFunction("each", arguments)
+	if err != nil {
+		return err
+	}
+
+	for _, element := range array.Elements {
+		if result := env.Call(function, element); isError(result) == true {
+			return result
+		}
+	}
+
+	return NIL
+}
+
+// Any function: any(array, func(element) { ... }), returns true when any result is truthy
+func Any(env *object.Environment, arguments ...object.Object) object.Object {
+	array, function, err := arrayAndFunction("any", arguments)
+	if err != nil {
+		return err
+	}
+
+	for _, element := range array.Elements {
+		result := env.Call(function, element)
+		if isError(result) == true {
+			return result
+		}
+
+		if isTruthy(result) == true {
+			return TRUE
+		}
+	}
+
+	return FALSE
+}
+
+// All function: all(array, func(element) { ... }), returns true when all results are truthy
+func All(env *object.Environment, arguments ...object.Object) object.Object {
+	array, function, err := arrayAndFunction("all", arguments)
+	if err != nil {
+		return err
+	}
+
+	for _, element := range array.Elements {
+		result := env.Call(function, element)
+		if isError(result) == true {
+			return result
+		}
+
+		if isTruthy(result) == false {
+			return FALSE
+		}
+	}
+
+	return TRUE
+}
+
+// Find function: find(array, func(element) { ... }), returns the first element which the result is truthy, or nil
+func Find(env *object.Environment, arguments ...object.Object) object.Object {
+	array, function, err := arrayAndFunction("find", arguments)
+	if err != nil {
+		return err
+	}
+
+	for _, element := range array.Elements {
+		result := env.Call(function, element)
+		if isError(result) == true {
+			return result
+		}
+
+		if isTruthy(result) == true {
+			return element
+		}
+	}
+
+	return NIL
+}
+
+// GroupBy function: group_by(array, func(element) { ... }), returns the hash of result to the array of elements
+func GroupBy(env *object.Environment, arguments ...object.Object) object.Object {
+	array, function, err := arrayAndFunction("group_by", arguments)
+	if err != nil {
+		return err
+	}
+
+	if err := env.Allocate(int64(len(array.Elements))); err != nil {
+		return err
+	}
+
+	groups := &object.Hash{
+		Order: []object.HashKey{},
+		Pairs: make(map[object.HashKey]object.HashPair),
+	}
+
+	for _, element := range array.Elements {
+		key := env.Call(function, element)
+		if isError(key) == true {
+			return key
+		}
+
+		hashed, err := hashKey(key)
+		if err != nil {
+			return err
+		}
+
+		pair, ok := groups.Pairs[hashed]
+		if ok == false {
+			pair = object.HashPair{Key: key, Value: &object.Array{Elements: []object.Object{}}}
+			groups.Order = append(groups.Order, hashed)
+		}
+
+		group := pair.Value.(*object.Array)
+		group.Elements = append(group.Elements, element)
+
+		groups.Pairs[hashed] = pair
+	}
+
+	return groups
+}
+
+// Sort function: sort(array), sort(array, func(a, b) { ... }), returns the new sorted array,
+// the comparator returns true or negative number when a should be placed before b
+func Sort(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("sort", arguments, 1, 2); err != nil {
+		return err
+	}
+
+	array, ok := arguments[0].(*object.Array)
+	if ok == false {
+		return argumentError("sort", 1, object.ARRAY_OBJECT, arguments[0])
+	}
+
+	less := func(left object.Object, right object.Object) (bool, *object.Error) {
+		return compare(left, right)
+	}
+
+	if len(arguments) == 2 {
+		function := arguments[1]
+		if isCallable(function) == false {
+			return argumentError("sort", 2, object.FUNCTION_OBJECT, function)
+		}
+
+		less = func(left object.Object, right object.Object) (bool, *object.Error) {
+			return compareBy(env, function, left, right)
+		}
+	}
+
+	if err := env.Allocate(int64(len(array.Elements))); err != nil {
+		return err
+	}
+
+	elements := make([]object.Object, len(array.Elements))
+	copy(elements, array.Elements)
+
+	// The sort cannot be stopped, so skip the comparisons after the first error
+	var sortError *object.Error
+
+	sort.SliceStable(elements, func(i, j int) bool {
+		if sortError != nil {
+			return false
+		}
+
+		result, err := less(elements[i], elements[j])
+		if err != nil {
+			sortError = err
+		}
+
+		return result
+	})
+
+	if sortError != nil {
+		return sortError
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// Zip function: zip(array1, array2, ...), returns the array of arrays which group the elements by index,
+// the length is the shortest array
+func Zip(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("zip", arguments, 1, -1); err != nil {
+		return err
+	}
+
+	arrays := make([]*object.Array, len(arguments))
+	length := -1
+
+	for index, argument := range arguments {
+		array, ok := argument.(*object.Array)
+		if ok == false {
+			return argumentError("zip", index+1, object.ARRAY_OBJECT, argument)
+		}
+
+		if length < 0 || len(array.Elements) < length {
+			length = len(array.Elements)
+		}
+
+		arrays[index] = array
+	}
+
+	if err := env.Allocate(int64(length * (len(arrays) + 1))); err != nil {
+		return err
+	}
+
+	elements := make([]object.Object, length)
+
+	for index := range elements {
+		group := make([]object.Object, len(arrays))
+
+		for arrayIndex, array := range arrays {
+			group[arrayIndex] = array.Elements[index]
+		}
+
+		elements[index] = &object.Array{Elements: group}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// Flatten function: flatten(array), flatten(array, depth), returns the new array which the nested arrays
+// are flattened to the depth, the default depth is 1
+func Flatten(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("flatten", arguments, 1, 2); err != nil {
+		return err
+	}
+
+	array, ok := arguments[0].(*object.Array)
+	if ok == false {
+		return argumentError("flatten", 1, object.ARRAY_OBJECT, arguments[0])
+	}
+
+	depth := int64(1)
+	if len(arguments) == 2 {
+		integer, ok := arguments[1].(*object.Integer)
+		if ok == false {
+			return argumentError("flatten", 2, object.INTEGER_OBJECT, arguments[1])
+		}
+
+		depth = integer.Value
+	}
+
+	elements := flatten(array.Elements, depth, []object.Object{})
+
+	if err := env.Allocate(int64(len(elements))); err != nil {
+		return err
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func flatten(elements []object.Object, depth int64, result []object.Object) []object.Object {
+	for _, element := range elements {
+		if array, ok := element.(*object.Array); ok && depth > 0 {
+			result = flatten(array.Elements, depth-1, result)
+		} else {
+			result = append(result, element)
+		}
+	}
+
+	return result
+}
+
+// Returns the array and function arguments of higher-order function, e.g. map(array, function)
+func arrayAndFunction(name string, arguments []object.Object) (*object.Array, object.Object, *object.Error) {
+	if err := checkArguments(name, arguments, 2, 2); err != nil {
+		return nil, nil, err
+	}
+
+	array, ok := arguments[0].(*object.Array)
+	if ok == false {
+		return nil, nil, argumentError(name, 1, object.ARRAY_OBJECT, arguments[0])
+	}
+
+	if isCallable(arguments[1]) == false {
+		return nil, nil, argumentError(name, 2, object.FUNCTION_OBJECT, arguments[1])
+	}
+
+	return array, arguments[1], nil
+}
+
+func isCallable(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Function, *object.BuiltIn, *object.BoundMethod, *object.Struct:
+		return true
+	default:
+		return false
+	}
+}
+
+// Compares the numbers or strings by natural order
+func compare(left object.Object, right object.Object) (bool, *object.Error) {
+	switch left := left.(type) {
+	case *object.Integer:
+		switch right := right.(type) {
+		case *object.Integer:
+			return left.Value < right.Value, nil
+		case *object.Float:
+			return float64(left.Value) < right.Value, nil
+		}
+	case *object.Float:
+		switch right := right.(type) {
+		case *object.Integer:
+			return left.Value < float64(right.Value), nil
+		case *object.Float:
+			return left.Value < right.Value, nil
+		}
+	case *object.String:
+		if right, ok := right.(*object.String); ok {
+			return left.Value < right.Value, nil
+		}
+	}
+
+	return false, newError("Cannot compare %s and %s", left.Type(), right.Type())
+}
+
+// Compares by the comparator function, it returns true or negative number when left should be placed before right
+func compareBy(env *object.Environment, function object.Object, left object.Object, right object.Object) (bool, *object.Error) {
+	result := env.Call(function, left, right)
+
+	switch result := result.(type) {
+	case *object.Error:
+		return false, result
+	case *object.Boolean:
+		return result.Value, nil
+	case *object.Integer:
+		return result.Value < 0, nil
+	case *object.Float:
+		return result.Value < 0, nil
+	default:
+		return false, newError("sort comparator must return BOOLEAN_OBJECT or number, Got: %s", result.Type())
+	}
+}
@@ -0,0 +1,87 @@
+package builtins
+
+import (
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// Keys function: keys(hash), returns the keys of hash in insertion order
+func Keys(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("keys", arguments, 1, 1); err != nil {
+		return err
+	}
+
+	hash, ok := arguments[0].(*object.Hash)
+	if ok == false {
+		return argumentError("keys", 1, object.HASH_OBJECT, arguments[0])
+	}
+
+	if err := env.Allocate(int64(len(hash.Order))); err != nil {
+		return err
+	}
+
+	elements := make([]object.Object, len(hash.Order))
+
+	for index, key := range hash.Order {
+		elements[index] = hash.Pairs[key].Key
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// Values function: values(hash), returns the values of hash in insertion order
+func Values(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("values", arguments, 1, 1); err != nil {
+		return err
+	}
+
+	hash, ok := arguments[0].(*object.Hash)
+	if ok == false {
+		return argumentError("values", 1, object.HASH_OBJECT, arguments[0])
+	}
+
+	if err := env.Allocate(int64(len(hash.Order))); err != nil {
+		return err
+	}
+
+	elements := make([]object.Object, len(hash.Order))
+
+	for index, key := range hash.Order {
+		elements[index] = hash.Pairs[key].Value
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// Delete function: delete(hash, key), removes the key from hash and returns its value, nil when the key is not exists
+func Delete(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("delete", arguments, 2, 2); err != nil {
+		return err
+	}
+
+	hash, ok := arguments[0].(*object.Hash)
+	if ok == false {
+		return argumentError("delete", 1, object.HASH_OBJECT, arguments[0])
+	}
+
+	key, err := hashKey(arguments[1])
+	if err != nil {
+		return err
+	}
+
+	pair, ok := hash.Pairs[key]
+	if ok == false {
+		return NIL
+	}
+
+	delete(hash.Pairs, key)
+
+	// Remove the key from the order but keep the order of other keys
+	for index, orderKey := range hash.Order {
+		if orderKey == key {
+			hash.Order = append(hash.Order[:index:index], hash.Order[index+1:]...)
+			break
+		}
+	}
+
+	return pair.Value
+}
@@ -0,0 +1,179 @@
+package builtins
+
+import (
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// Returns error when the count of arguments is not between minimum and maximum, the negative maximum means no limit
+func checkArguments(name string, arguments []object.Object, minimum int, maximum int) *object.Error {
+	count := len(arguments)
+
+	if count >= minimum && (maximum < 0 || count <= maximum) {
+		return nil
+	}
+
+	switch {
+	case maximum < 0:
+		return newError("wrong number of arguments for %s, Got: %d, Expected: at least %d", name, count, minimum)
+	case minimum == maximum:
+		return newError("wrong number of arguments for %s, Got: %d, Expected: %d", name, count, minimum)
+	case minimum+1 == maximum:
+		return newError("wrong number of arguments for %s, Got: %d, Expected: %d or %d", name, count, minimum, maximum)
+	default:
+		return newError("wrong number of arguments for %s, Got: %d, Expected: %d to %d", name, count, minimum, maximum)
+	}
+}
+
+func argumentError(name string, position int, expected string, argument object.Object) *object.Error {
+	return newError("argument %d of %s must be %s, Got: %s", position, name, expected, argument.Type())
+}
+
+// Returns the string value of argument at index, the position in error message starts from 1
+func stringArgument(name string, arguments []object.Object, index int) (string, *object.Error) {
+	str, ok := arguments[index].(*object.String)
+	if ok == false {
+		return "", argumentError(name, index+1, object.STRING_OBJECT, arguments[index])
+	}
+
+	return str.Value, nil
+}
+
+// Returns the integer value of argument at index, the position in error message starts from 1
+func integerArgument(name string, arguments []object.Object, index int) (int64, *object.Error) {
+	integer, ok := arguments[index].(*object.Integer)
+	if ok == false {
+		return 0, argumentError(name, index+1, object.INTEGER_OBJECT, arguments[index])
+	}
+
+	return integer.Value, nil
+}
+
+// Returns the hash key of object or error when the object cannot be used as hash key
+func hashKey(key object.Object) (object.HashKey, *object.Error) {
+	hashable, ok := key.(object.Hashable)
+	if ok == false {
+		return object.HashKey{}, newError("Cannot use %s as hash key", key.Type())
+	}
+
+	return hashable.HashKey(), nil
+}
+
+// Compares the objects by value like == operator, the collections are compared by their items
+func equals(left object.Object, right object.Object) bool {
+	switch left := left.(type) {
+	case *object.Nil:
+		_, ok := right.(*object.Nil)
+
+		return ok
+	case *object.Boolean:
+		right, ok := right.(*object.Boolean)
+
+		return ok && left.Value == right.Value
+	case *object.Integer:
+		switch right := right.(type) {
+		case *object.Integer:
+			return left.Value == right.Value
+		case *object.Float:
+			return float64(left.Value) == right.Value
+		}
+
+		return false
+	case *object.Float:
+		switch right := right.(type) {
+		case *object.Integer:
+			return left.Value == float64(right.Value)
+		case *object.Float:
+			return left.Value == right.Value
+		}
+
+		return false
+	case *object.String:
+		right, ok := right.(*object.String)
+
+		return ok && left.Value == right.Value
+	case *object.Array:
+		right, ok := right.(*object.Array)
+		if ok == false || len(left.Elements) != len(right.Elements) {
+			return false
+		}
+
+		for index := range left.Elements {
+			if equals(left.Elements[index], right.Elements[index]) == false {
+				return false
+			}
+		}
+
+		return true
+	case *object.Hash:
+		right, ok := right.(*object.Hash)
+		if ok == false || len(left.Pairs) != len(right.Pairs) {
+			return false
+		}
+
+		for key, leftPair := range left.Pairs {
+			rightPair, ok := right.Pairs[key]
+			if ok == false || equals(leftPair.Value, rightPair.Value) == false {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return left == right
+	}
+}
+
+// Returns the range of slice like Python, the negative index counts from the end and the index will be clamped
+func sliceRange(start int64, end int64, length int) (int, int) {
+	clamp := func(index int64) int {
+		if index < 0 {
+			index = index + int64(length)
+		}
+
+		switch {
+		case index < 0:
+			return 0
+		case index > int64(length):
+			return length
+		default:
+			return int(index)
+		}
+	}
+
+	from, to := clamp(start), clamp(end)
+	if from > to {
+		return from, from
+	}
+
+	return from, to
+}
+
+func isError(obj object.Object) bool {
+	if obj != nil {
+		return obj.Type() == object.ERROR_OBJECT
+	}
+
+	return false
+}
+
+// Reports the object is true or not like the condition of if expression
+func isTruthy(obj object.Object) bool {
+	switch obj := obj.(type) {
+	case *object.Boolean:
+		return obj.Value
+	case *object.Nil:
+		return false
+	case *object.String:
+		return len(obj.Value) != 0
+	case *object.Integer:
+		return obj.Value != 0
+	case *object.Float:
+		return obj.Value != 0.0
+	case *object.Array:
+		return len(obj.Elements) != 0
+	case *object.Hash:
+		return len(obj.Pairs) != 0
+	default:
+		return true
+	}
+}
@@ -0,0 +1,42 @@
+package builtins
+
+import (
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// Methods of built-in types, the receiver is passed to the built-in function as the first argument,
+// e.g. "abc".upper() is upper("abc"), it is shared by all programs so it must not be modified,
+// register the method to the environment of program by SetMethod instead
+var Methods = map[object.ObjectType]map[string]*object.BuiltIn{
+	object.STRING_OBJECT: methodsOf(
+		"len", "contains", "index_of", "reverse", "slice",
+		"split", "replace", "trim", "trim_left", "trim_right", "trim_prefix", "trim_suffix",
+		"upper", "lower", "starts_with", "ends_with", "repeat", "pad_left", "pad_right", "chars", "format",
+		"to_int", "to_float", "to_string",
+	),
+	object.ARRAY_OBJECT: methodsOf(
+		"len", "push", "pop", "shift", "insert", "contains", "index_of", "reverse", "slice", "join",
+		"map", "filter", "reduce", "each", "any", "all", "find", "zip", "flatten", "group_by", "sort",
+		"to_string",
+	),
+	object.HASH_OBJECT: methodsOf(
+		"len", "keys", "values", "delete", "contains",
+		"to_string",
+	),
+	object.INTEGER_OBJECT: methodsOf(
+		"to_int", "to_float", "to_string",
+	),
+	object.FLOAT_OBJECT: methodsOf(
+		"to_int", "to_float", "to_string",
+	),
+}
+
+func methodsOf(names ...string) map[string]*object.BuiltIn {
+	methods := make(map[string]*object.BuiltIn, len(names))
+
+	for _, name := range names {
+		methods[name] = BuiltIns[name]
+	}
+
+	return methods
+}
@@ -9,7 +9,7 @@ import (
 // Print function: print(arg1, arg2, ...)
 func Print(env *object.Environment, arguments ...object.Object) object.Object {
 	if len(arguments) == 0 {
-		fmt.Print("")
+		fmt.Fprint(env.Stdout(), "")
 
 		return NIL
 	}
@@ -20,7 +20,7 @@ func Print(env *object.Environment, arguments ...object.Object) object.Object {
 		parameters[index] = argument.Inspect()
 	}
 
-	fmt.Print(parameters...)
+	fmt.Fprint(env.Stdout(), parameters...)
 
 	return NIL
 }
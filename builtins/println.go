@@ -9,7 +9,7 @@ import (
 // Println function: println(arg1, arg2, ...)
 func Println(env *object.Environment, arguments ...object.Object) object.Object {
 	if len(arguments) == 0 {
-		fmt.Println("")
+		fmt.Fprintln(env.Stdout(), "")
 
 		return NIL
 	}
@@ -20,7 +20,7 @@ func Println(env *object.Environment, arguments ...object.Object) object.Object
 		parameters[index] = argument.Inspect()
 	}
 
-	fmt.Println(parameters...)
+	fmt.Fprintln(env.Stdout(), parameters...)
 
 	return NIL
 }
@@ -0,0 +1,445 @@
+package builtins
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// The maximum length of string which created by repeat and pad functions
+const maxStringLength = 1 << 30
+
+// Split function: split(string, separator), returns the array of substrings, the empty separator splits each character
+func Split(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("split", arguments, 2, 2); err != nil {
+		return err
+	}
+
+	str, err := stringArgument("split", arguments, 0)
+	if err != nil {
+		return err
+	}
+
+	separator, err := stringArgument("split", arguments, 1)
+	if err != nil {
+		return err
+	}
+
+	return newStringArray(env, strings.Split(str, separator))
+}
+
+// Join function: join(array, separator), returns the string of elements which joined by separator
+func Join(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("join", arguments, 2, 2); err != nil {
+		return err
+	}
+
+	array, ok := arguments[0].(*object.Array)
+	if ok == false {
+		return argumentError("join", 1, object.ARRAY_OBJECT, arguments[0])
+	}
+
+	separator, err := stringArgument("join", arguments, 1)
+	if err != nil {
+		return err
+	}
+
+	values := make([]string, len(array.Elements))
+
+	for index, element := range array.Elements {
+		values[index] = element.Inspect()
+	}
+
+	return &object.String{Value: strings.Join(values, separator)}
+}
+
+// Replace function: replace(string, old, new), replace(string, old, new, count), replaces all old substrings
+// when the count is not provided or negative
+func Replace(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("replace", arguments, 3, 4); err != nil {
+		return err
+	}
+
+	values := make([]string, 3)
+
+	for index := range values {
+		value, err := stringArgument("replace", arguments, index)
+		if err != nil {
+			return err
+		}
+
+		values[index] = value
+	}
+
+	count := int64(-1)
+	if len(arguments) == 4 {
+		value, err := integerArgument("replace", arguments, 3)
+		if err != nil {
+			return err
+		}
+
+		count = value
+	}
+
+	return &object.String{Value: strings.Replace(values[0], values[1], values[2], int(count))}
+}
+
+// Trim function: trim(string), trim(string, cutset), removes the leading and trailing white spaces or characters in cutset
+func Trim(env *object.Environment, arguments ...object.Object) object.Object {
+	return trimString("trim", arguments, strings.TrimSpace, strings.Trim)
+}
+
+// TrimLeft function: trim_left(string), trim_left(string, cutset)
+func TrimLeft(env *object.Environment, arguments ...object.Object) object.Object {
+	trimSpace := func(value string) string {
+		return strings.TrimLeftFunc(value, unicode.IsSpace)
+	}
+
+	return trimString("trim_left", arguments, trimSpace, strings.TrimLeft)
+}
+
+// TrimRight function: trim_right(string), trim_right(string, cutset)
+func TrimRight(env *object.Environment, arguments ...object.Object) object.Object {
+	trimSpace := func(value string) string {
+		return strings.TrimRightFunc(value, unicode.IsSpace)
+	}
+
+	return trimString("trim_right", arguments, trimSpace, strings.TrimRight)
+}
+
+// TrimPrefix function: trim_prefix(string, prefix), removes the prefix once
+func TrimPrefix(env *object.Environment, arguments ...object.Object) object.Object {
+	return stringPairFunction("trim_prefix", arguments, func(value string, prefix string) object.Object {
+		return &object.String{Value: strings.TrimPrefix(value, prefix)}
+	})
+}
+
+// TrimSuffix function: trim_suffix(string, suffix), removes the suffix once
+func TrimSuffix(env *object.Environment, arguments ...object.Object) object.Object {
+	return stringPairFunction("trim_suffix", arguments, func(value string, suffix string) object.Object {
+		return &object.String{Value: strings.TrimSuffix(value, suffix)}
+	})
+}
+
+// Upper function: upper(string)
+func Upper(env *object.Environment, arguments ...object.Object) object.Object {
+	return stringFunction("upper", arguments, strings.ToUpper)
+}
+
+// Lower function: lower(string)
+func Lower(env *object.Environment, arguments ...object.Object) object.Object {
+	return stringFunction("lower", arguments, strings.ToLower)
+}
+
+// StartsWith function: starts_with(string, prefix)
+func StartsWith(env *object.Environment, arguments ...object.Object) object.Object {
+	return stringPairFunction("starts_with", arguments, func(value string, prefix string) object.Object {
+		return nativeBoolToBooleanObject(strings.HasPrefix(value, prefix))
+	})
+}
+
+// EndsWith function: ends_with(string, suffix)
+func EndsWith(env *object.Environment, arguments ...object.Object) object.Object {
+	return stringPairFunction("ends_with", arguments, func(value string, suffix string) object.Object {
+		return nativeBoolToBooleanObject(strings.HasSuffix(value, suffix))
+	})
+}
+
+// Repeat function: repeat(string, count)
+func Repeat(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("repeat", arguments, 2, 2); err != nil {
+		return err
+	}
+
+	str, err := stringArgument("repeat", arguments, 0)
+	if err != nil {
+		return err
+	}
+
+	count, err := integerArgument("repeat", arguments, 1)
+	if err != nil {
+		return err
+	}
+
+	if count < 0 {
+		return newError("repeat count must not be negative, Got: %d", count)
+	}
+
+	if len(str) > 0 && count > maxStringLength/int64(len(str)) {
+		return newError("repeat result is too long, Got: %d * %d", len(str), count)
+	}
+
+	return &object.String{Value: strings.Repeat(str, int(count))}
+}
+
+// PadLeft function: pad_left(string, width), pad_left(string, width, pad), pads the string to the width
+// by the pad string (default is space) at the left side
+func PadLeft(env *object.Environment, arguments ...object.Object) object.Object {
+	return padString("pad_left", arguments, func(str string, padding string) string {
+		return padding + str
+	})
+}
+
+// PadRight function: pad_right(string, width), pad_right(string, width, pad)
+func PadRight(env *object.Environment, arguments ...object.Object) object.Object {
+	return padString("pad_right", arguments, func(str string, padding string) string {
+		return str + padding
+	})
+}
+
+// Chars function: chars(string), returns the array of characters
+func Chars(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("chars", arguments, 1, 1); err != nil {
+		return err
+	}
+
+	str, err := stringArgument("chars", arguments, 0)
+	if err != nil {
+		return err
+	}
+
+	values := make([]string, 0, len(str))
+
+	for _, char := range str {
+		values = append(values, string(char))
+	}
+
+	return newStringArray(env, values)
+}
+
+// Format function: format(format, value1, value2, ...), formats the values by Go-style verbs like %d, %s, %.2f and %v
+func Format(env *object.Environment, arguments ...object.Object) object.Object {
+	if err := checkArguments("format", arguments, 1, -1); err != nil {
+		return err
+	}
+
+	format, err := stringArgument("format", arguments, 0)
+	if err != nil {
+		return err
+	}
+
+	var builder strings.Builder
+
+	position := 1 // the next argument
+
+	for index := 0; index < len(format); index++ {
+		if format[index] != '%' {
+			builder.WriteByte(format[index])
+			continue
+		}
+
+		// The verb like %-5.2f is formatted by its flags, width and precision
+		end := index + 1
+		for end < len(format) && strings.IndexByte("+-# 0123456789.", format[end]) >= 0 {
+			end++
+		}
+
+		if end >= len(format) {
+			return newError("missing verb at the end of format %q", format)
+		}
+
+		verb, size := utf8.DecodeRuneInString(format[end:])
+		spec := format[index : end+size]
+		index = end + size - 1
+
+		if verb == '%' {
+			builder.WriteByte('%')
+			continue
+		}
+
+		if position >= len(arguments) {
+			return newError("missing argument %d of format for %s", position+1, spec)
+		}
+
+		value, err := formatValue(verb, spec, arguments, position)
+		if err != nil {
+			return err
+		}
+
+		builder.WriteString(fmt.Sprintf(spec, value))
+		position++
+	}
+
+	if position < len(arguments) {
+		return newError("too many arguments for format, Got: %d, Expected: %d", len(arguments), position)
+	}
+
+	return &object.String{Value: builder.String()}
+}
+
+// Converts the argument to the Go value which expected by the verb of format
+func formatValue(verb rune, spec string, arguments []object.Object, position int) (interface{}, *object.Error) {
+	argument := arguments[position]
+
+	switch verb {
+	// integer
+	case 'd', 'b', 'o', 'c', 'U':
+		if integer, ok := argument.(*object.Integer); ok {
+			return integer.Value, nil
+		}
+
+		return nil, argumentError("format", position+1, object.INTEGER_OBJECT+" for "+spec, argument)
+	// integer, float or string in hex
+	case 'x', 'X':
+		switch argument := argument.(type) {
+		case *object.Integer:
+			return argument.Value, nil
+		case *object.Float:
+			return argument.Value, nil
+		case *object.String:
+			return argument.Value, nil
+		}
+
+		return nil, argumentError("format", position+1, object.INTEGER_OBJECT+" for "+spec, argument)
+	// float, the integer is converted to float
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		switch argument := argument.(type) {
+		case *object.Float:
+			return argument.Value, nil
+		case *object.Integer:
+			return float64(argument.Value), nil
+		}
+
+		return nil, argumentError("format", position+1, object.FLOAT_OBJECT+" for "+spec, argument)
+	case 't':
+		if boolean, ok := argument.(*object.Boolean); ok {
+			return boolean.Value, nil
+		}
+
+		return nil, argumentError("format", position+1, object.BOOLEAN_OBJECT+" for "+spec, argument)
+	// string, the other objects are formatted like print
+	case 's', 'q':
+		if str, ok := argument.(*object.String); ok {
+			return str.Value, nil
+		}
+
+		return argument.Inspect(), nil
+	case 'v':
+		switch argument := argument.(type) {
+		case *object.Integer:
+			return argument.Value, nil
+		case *object.Float:
+			return argument.Value, nil
+		case *object.String:
+			return argument.Value, nil
+		case *object.Boolean:
+			return argument.Value, nil
+		}
+
+		return argument.Inspect(), nil
+	}
+
+	return nil, newError("unknown verb %s in format", spec)
+}
+
+func trimString(name string, arguments []object.Object, trimSpace func(string) string, trimCutset func(string, string) string) object.Object {
+	if err := checkArguments(name, arguments, 1, 2); err != nil {
+		return err
+	}
+
+	str, err := stringArgument(name, arguments, 0)
+	if err != nil {
+		return err
+	}
+
+	if len(arguments) == 1 {
+		return &object.String{Value: trimSpace(str)}
+	}
+
+	cutset, err := stringArgument(name, arguments, 1)
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Value: trimCutset(str, cutset)}
+}
+
+func padString(name string, arguments []object.Object, pad func(string, string) string) object.Object {
+	if err := checkArguments(name, arguments, 2, 3); err != nil {
+		return err
+	}
+
+	str, err := stringArgument(name, arguments, 0)
+	if err != nil {
+		return err
+	}
+
+	width, err := integerArgument(name, arguments, 1)
+	if err != nil {
+		return err
+	}
+
+	padding := " "
+	if len(arguments) == 3 {
+		if padding, err = stringArgument(name, arguments, 2); err != nil {
+			return err
+		}
+
+		if padding == "" {
+			return newError("%s pad must not be empty", name)
+		}
+	}
+
+	if width > maxStringLength {
+		return newError("%s width is too long, Got: %d", name, width)
+	}
+
+	// The width is counted by characters, the pad string will be cut when it cannot fit the width
+	length := int64(utf8.RuneCountInString(str))
+	if width <= length {
+		return &object.String{Value: str}
+	}
+
+	padLength := int(width - length)
+	padChars := []rune(strings.Repeat(padding, padLength/utf8.RuneCountInString(padding)+1))
+
+	return &object.String{Value: pad(str, string(padChars[:padLength]))}
+}
+
+func stringFunction(name string, arguments []object.Object, function func(string) string) object.Object {
+	if err := checkArguments(name, arguments, 1, 1); err != nil {
+		return err
+	}
+
+	str, err := stringArgument(name, arguments, 0)
+	if err != nil {
+		return err
+	}
+
+	return &object.String{Value: function(str)}
+}
+
+func stringPairFunction(name string, arguments []object.Object, function func(string, string) object.Object) object.Object {
+	if err := checkArguments(name, arguments, 2, 2); err != nil {
+		return err
+	}
+
+	first, err := stringArgument(name, arguments, 0)
+	if err != nil {
+		return err
+	}
+
+	second, err := stringArgument(name, arguments, 1)
+	if err != nil {
+		return err
+	}
+
+	return function(first, second)
+}
+
+func newStringArray(env *object.Environment, values []string) object.Object {
+	if err := env.Allocate(int64(len(values))); err != nil {
+		return err
+	}
+
+	elements := make([]object.Object, len(values))
+
+	for index, value := range values {
+		elements[index] = &object.String{Value: value}
+	}
+
+	return &object.Array{Elements: elements}
+}
@@ -8,11 +8,8 @@ import (
 	"github.com/c-bata/go-prompt"
 	"github.com/urfave/cli"
 
-	"github.com/zeuxisoo/go-skrip/evaluator"
-	"github.com/zeuxisoo/go-skrip/lexer"
+	"github.com/zeuxisoo/go-skrip"
 	"github.com/zeuxisoo/go-skrip/object"
-	"github.com/zeuxisoo/go-skrip/parser"
-	"github.com/zeuxisoo/go-skrip/pkg/logger"
 )
 
 var Cli = cli.Command{
@@ -20,6 +17,10 @@ var Cli = cli.Command{
 	Usage:       "Start console mode",
 	Description: "Start console mode to execute code",
 	Action:      runCli,
+	Flags: []cli.Flag{
+		engineFlag,
+		allowFlag,
+	},
 }
 
 var LivePrefixState struct {
@@ -30,45 +31,44 @@ var LivePrefixState struct {
 var keywords = []string{
 	"func", "let", "true", "false", "if", "else",
 	"return", "for", "in", "nil", "break", "continue",
+	"import", "as", "try", "catch", "finally", "throw",
+	"match", "struct",
 }
 
-var code = ""
-var leftBraceCount = 0
-var rightBraceCount = 0
-
-var env *object.Environment
+// repl keeps the interpreter and the unfinished multiple line code of console session
+type repl struct {
+	interpreter     *skrip.Interpreter
+	code            string
+	leftBraceCount  int
+	rightBraceCount int
+}
 
-func init() {
-	env = object.NewEnvironment()
+func newRepl(interpreter *skrip.Interpreter) *repl {
+	return &repl{
+		interpreter: interpreter,
+	}
 }
 
-func runCode(code string) {
-	theLexer := lexer.NewLexer(code)
-	theParser := parser.NewParser(theLexer)
-	theProgram := theParser.Parse()
+func (r *repl) runCode(code string) {
+	result, err := r.interpreter.Eval(code)
 
-	if len(theParser.Errors()) > 0 {
-		for _, message := range theParser.Errors() {
-			logger.Error(message)
+	switch err := err.(type) {
+	case *skrip.ParseError:
+		r.interpreter.PrintError(err)
+	case *skrip.RuntimeError:
+		if code, ok := err.ExitCode(); ok == true {
+			os.Exit(code)
 		}
-	} else {
-		theEvaluator := evaluator.Eval(theProgram, env)
 
-		if theEvaluator != nil {
-			if theEvaluator.Type() == object.ERROR_OBJECT {
-				fmt.Println("Error")
-			}
-
-			if theEvaluator.Type() == object.NIL_OBJECT {
-				fmt.Println()
-			}
-		} else {
+		r.interpreter.PrintError(err)
+	default:
+		if result == nil || result.Type() == object.NIL_OBJECT {
 			fmt.Println()
 		}
 	}
 }
 
-func executor(line string) {
+func (r *repl) executor(line string) {
 	if strings.ToLower(strings.TrimSpace(line)) == "exit" {
 		os.Exit(0)
 	}
@@ -78,8 +78,8 @@ func executor(line string) {
 	// 2. increase the left brace count
 	// 2. change live prefix to ..
 	if strings.HasSuffix(line, "{") {
-		code = code + line
-		leftBraceCount = leftBraceCount + 1
+		r.code = r.code + line
+		r.leftBraceCount = r.leftBraceCount + 1
 
 		LivePrefixState.LivePrefix = ".. "
 		LivePrefixState.IsEnable = true
@@ -95,31 +95,31 @@ func executor(line string) {
 	//		2. reset left and right brace count
 	//		3. run all saved code
 	//		4. clean all saved code
-	if strings.HasSuffix(line, "}") && leftBraceCount > 0 {
-		rightBraceCount = rightBraceCount + 1
+	if strings.HasSuffix(line, "}") && r.leftBraceCount > 0 {
+		r.rightBraceCount = r.rightBraceCount + 1
 
-		if leftBraceCount == rightBraceCount {
-			code = code + line
+		if r.leftBraceCount == r.rightBraceCount {
+			r.code = r.code + line
 
 			LivePrefixState.LivePrefix = ">> "
 			LivePrefixState.IsEnable = true
 
-			leftBraceCount = 0
-			rightBraceCount = 0
+			r.leftBraceCount = 0
+			r.rightBraceCount = 0
 
-			runCode(code)
+			r.runCode(r.code)
 
-			code = ""
+			r.code = ""
 		}
 
 		return
 	}
 
 	// Single line code
-	if leftBraceCount == 0 && rightBraceCount == 0 {
-		runCode(line)
+	if r.leftBraceCount == 0 && r.rightBraceCount == 0 {
+		r.runCode(line)
 	} else {
-		code = code + line
+		r.code = r.code + line
 	}
 }
 
@@ -164,8 +164,13 @@ func runCli(c *cli.Context) error {
 		},
 	}
 
+	theInterpreter := skrip.New(newOptions(c)...)
+	theInterpreter.Set("args", newArgs(c.Args()))
+
+	theRepl := newRepl(theInterpreter)
+
 	thePrompt := prompt.New(
-		executor, completer,
+		theRepl.executor, completer,
 		prompt.OptionPrefix(">> "),
 		prompt.OptionTitle("skrip repl"),
 		prompt.OptionLivePrefix(changeLivePrefixState),
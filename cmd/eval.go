@@ -1,15 +1,11 @@
 package cmd
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/urfave/cli"
 
-	"github.com/zeuxisoo/go-skrip/evaluator"
-	"github.com/zeuxisoo/go-skrip/lexer"
-	"github.com/zeuxisoo/go-skrip/object"
-	"github.com/zeuxisoo/go-skrip/parser"
+	"github.com/zeuxisoo/go-skrip"
 	"github.com/zeuxisoo/go-skrip/pkg/logger"
 )
 
@@ -18,10 +14,15 @@ var Eval = cli.Command{
 	Usage:       "Eval the inline code",
 	Description: "Eval the provided inline code",
 	Action:      runEval,
+	Flags: []cli.Flag{
+		engineFlag,
+		allowFlag,
+	},
 }
 
 func runEval(c *cli.Context) error {
 	code := c.Args().Get(0)
+	theOptions := newOptions(c)
 
 	cleanCode := strings.TrimSpace(code)
 
@@ -29,24 +30,12 @@ func runEval(c *cli.Context) error {
 		logger.Fatal("Please enter the code to eval")
 	}
 
-	theLexer := lexer.NewLexer(cleanCode)
-	theParser := parser.NewParser(theLexer)
-	theProgram := theParser.Parse()
-
-	if len(theParser.Errors()) > 0 {
-		for _, message := range theParser.Errors() {
-			logger.Error(message)
-		}
-	} else {
-		theEnvironment := object.NewEnvironment()
-		theEvaluator := evaluator.Eval(theProgram, theEnvironment)
-
-		if theEvaluator != nil {
-			if theEvaluator.Type() == object.ERROR_OBJECT {
-				fmt.Println("Error")
-			}
-		}
-	}
+	theInterpreter := skrip.New(theOptions...)
+	theInterpreter.Set("args", newArgs(c.Args().Tail()))
+
+	_, err := theInterpreter.Eval(cleanCode)
+
+	exitError(theInterpreter, err)
 
 	return nil
 }
@@ -1,15 +1,13 @@
 package cmd
 
 import (
-	"io/ioutil"
+	"os"
 	"strings"
 
 	"github.com/urfave/cli"
 
-	"github.com/zeuxisoo/go-skrip/evaluator"
-	"github.com/zeuxisoo/go-skrip/lexer"
+	"github.com/zeuxisoo/go-skrip"
 	"github.com/zeuxisoo/go-skrip/object"
-	"github.com/zeuxisoo/go-skrip/parser"
 	"github.com/zeuxisoo/go-skrip/pkg/logger"
 )
 
@@ -19,37 +17,111 @@ var Run = cli.Command{
 	Usage:       "Run the script",
 	Description: "Run the provided script file",
 	Action:      runRun,
+	Flags: []cli.Flag{
+		engineFlag,
+		allowFlag,
+	},
+}
+
+// Engine flag for choose the tree walking evaluator or bytecode vm
+var engineFlag = cli.StringFlag{
+	Name:  "engine",
+	Value: "evaluator",
+	Usage: "execution engine (evaluator, vm), the vm runs the program by evaluator when it uses the features not compiled to bytecode yet",
+}
+
+// Allow flag for grant the capabilities of built-in functions, all capabilities are granted when it is not set
+var allowFlag = cli.StringFlag{
+	Name:  "allow",
+	Usage: "comma separated capabilities to grant (io, fs, net, exec, time, env), e.g. --allow=io,env",
+}
+
+// Exit codes of command, the exit function of script will exit with its own code
+const (
+	ExitCodeParseError   = 65 // same as EX_DATAERR in sysexits.h
+	ExitCodeRuntimeError = 70 // same as EX_SOFTWARE in sysexits.h
+)
+
+func findEngine(name string) skrip.Engine {
+	switch engine := skrip.Engine(name); engine {
+	case skrip.EvaluatorEngine, skrip.VMEngine:
+		return engine
+	default:
+		logger.Fatal("Unknown engine: %s", name)
+	}
+
+	return ""
+}
+
+// Returns the options of interpreter by the command flags
+func newOptions(c *cli.Context) []skrip.Option {
+	options := []skrip.Option{
+		skrip.WithEngine(findEngine(c.String("engine"))),
+	}
+
+	if c.IsSet("allow") == true {
+		capabilities, err := object.ParseCapabilities(c.String("allow"))
+		if err != nil {
+			logger.Fatal("%v", err)
+		}
+
+		options = append(options, skrip.WithCapabilities(capabilities...))
+	}
+
+	return options
 }
 
 func runRun(c *cli.Context) error {
 	filePath := c.Args().Get(0)
+	theOptions := newOptions(c)
 
 	if len(strings.TrimSpace(filePath)) <= 0 {
 		logger.Fatal("Please enter the script file path")
 	}
 
-	contentBytes, err := ioutil.ReadFile(filePath)
-	if err != nil {
+	theInterpreter := skrip.New(theOptions...)
+	theInterpreter.Set("args", newArgs(c.Args().Tail()))
+
+	_, err := theInterpreter.EvalFile(filePath)
+	if _, ok := err.(*os.PathError); ok {
 		logger.Error("Cannot open the script file")
-		logger.Fatal("%v", err)
 	}
 
-	theLexer := lexer.NewLexer(string(contentBytes))
-	theParser := parser.NewParser(theLexer)
-	theProgram := theParser.Parse()
+	exitError(theInterpreter, err)
 
-	if len(theParser.Errors()) > 0 {
-		for _, message := range theParser.Errors() {
-			logger.Error(message)
-		}
-	} else {
-		theEnvironment := object.NewEnvironment()
-		theEvaluator := evaluator.Eval(theProgram, theEnvironment)
+	return nil
+}
 
-		if theEvaluator == nil {
-			// nothing todo
-		}
+// Convert the command arguments after the script to array
+func newArgs(values []string) *object.Array {
+	elements := make([]object.Object, len(values))
+
+	for index, value := range values {
+		elements[index] = &object.String{Value: value}
 	}
 
-	return nil
+	return &object.Array{Elements: elements}
+}
+
+// Print the parse errors or the runtime error with the call stack to the stderr of interpreter like panic and exit,
+// nothing to do when there is no error
+func exitError(interpreter *skrip.Interpreter, err error) {
+	switch err := err.(type) {
+	case nil:
+		return
+	case *skrip.ParseError:
+		interpreter.PrintError(err)
+
+		os.Exit(ExitCodeParseError)
+	case *skrip.RuntimeError:
+		if code, ok := err.ExitCode(); ok == true {
+			os.Exit(code)
+		}
+
+		interpreter.PrintError(err)
+
+		os.Exit(ExitCodeRuntimeError)
+	default:
+		logger.Fatal("%v", err)
+	}
 }
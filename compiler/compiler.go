@@ -0,0 +1,840 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/zeuxisoo/go-skrip/ast"
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+var (
+	BREAK    = &object.Break{}
+	CONTINUE = &object.Continue{}
+)
+
+// Bytecode is the compiled program for vm
+type Bytecode struct {
+	Instructions Instructions
+	Positions    []object.SourcePosition
+	Constants    []object.Object
+	Globals      map[string]int // global variable name and its index
+}
+
+// UnsupportedError will be returned when the node cannot be compiled to bytecode yet,
+// or its operands are too large for the instruction (e.g. too many local variables)
+type UnsupportedError struct {
+	Node   ast.Node
+	Reason string
+}
+
+func (u *UnsupportedError) Error() string {
+	if u.Reason != "" {
+		return fmt.Sprintf("unsupported %T: %s, %s", u.Node, u.Node.String(), u.Reason)
+	}
+
+	return fmt.Sprintf("unsupported %T: %s", u.Node, u.Node.String())
+}
+
+type emittedInstruction struct {
+	Opcode   Opcode
+	Position int
+}
+
+type loopScope struct {
+	start  int   // jump target of continue
+	breaks []int // positions of break jumps which will be patched to the end of loop
+}
+
+type compilationScope struct {
+	instructions        Instructions
+	positions           []object.SourcePosition
+	lastInstruction     emittedInstruction
+	previousInstruction emittedInstruction
+	loops               []*loopScope
+}
+
+type Compiler struct {
+	constants   []object.Object
+	symbolTable *SymbolTable
+	node        ast.Node // the compiling node for mapping the emitted instructions to source position
+
+	scopes     []compilationScope
+	scopeIndex int
+
+	overflow error // the first operand which cannot be encoded, it is returned when the compiling node finished
+}
+
+func NewCompiler() *Compiler {
+	mainScope := compilationScope{
+		instructions: Instructions{},
+	}
+
+	return &Compiler{
+		constants:   []object.Object{},
+		symbolTable: NewSymbolTable(),
+		scopes:      []compilationScope{mainScope},
+		scopeIndex:  0,
+	}
+}
+
+// NewCompilerWithState creates the compiler which continues the symbols of previous compilation,
+// the constant pool is created for each compilation, so it will not be filled up by the evaluations
+func NewCompilerWithState(symbolTable *SymbolTable) *Compiler {
+	compiler := NewCompiler()
+	compiler.symbolTable = symbolTable
+
+	return compiler
+}
+
+func (c *Compiler) Compile(node ast.Node) (err error) {
+	parentNode := c.node
+	c.node = node
+
+	defer func() {
+		c.node = parentNode
+
+		if err == nil && c.overflow != nil {
+			err = c.overflow
+		}
+	}()
+
+	switch node := node.(type) {
+	case *ast.Program:
+		return c.compileStatements(node.Statements)
+	// Statements
+	case *ast.LetStatement:
+		return c.compileLetStatement(node)
+	case *ast.ReturnStatement:
+		return c.compileReturnStatement(node)
+	case *ast.FunctionStatement:
+		return c.compileFunctionStatement(node)
+	case *ast.ExpressionStatement:
+		return c.compileExpressionStatement(node)
+	// Expressions
+	case *ast.IntegerLiteralExpression:
+		c.emit(OpConstant, c.addConstant(&object.Integer{Value: node.Value}))
+	case *ast.FloatLiteralExpression:
+		c.emit(OpConstant, c.addConstant(&object.Float{Value: node.Value}))
+	case *ast.StringLiteralExpression:
+		c.emit(OpConstant, c.addConstant(&object.String{Value: node.Value}))
+	case *ast.NilLiteralExpression:
+		c.emit(OpNil)
+	case *ast.BooleanExpression:
+		if node.Value == true {
+			c.emit(OpTrue)
+		} else {
+			c.emit(OpFalse)
+		}
+	case *ast.IdentifierExpression:
+		c.compileIdentifierExpression(node)
+	case *ast.AssignExpression:
+		return c.compileAssignExpression(node)
+	case *ast.TemplateLiteralExpression:
+		return c.compileTemplateLiteralExpression(node)
+	case *ast.ArrayLiteralExpression:
+		return c.compileArrayLiteralExpression(node)
+	case *ast.HashLiteralExpression:
+		return c.compileHashLiteralExpression(node)
+	case *ast.FunctionLiteralExpression:
+		return c.compileFunctionLiteralExpression(node, "")
+	case *ast.RangeExpression:
+		return c.compileOperands(OpRange, node.Start, node.End)
+	case *ast.CallExpression:
+		return c.compileCallExpression(node)
+	case *ast.IndexExpression:
+		return c.compileOperands(OpIndex, node.Left, node.Index)
+	case *ast.DotExpression:
+		return c.compileOperands(OpDot, node.Left, node.Item)
+	case *ast.PrefixExpression:
+		return c.compilePrefixExpression(node)
+	case *ast.InfixExpression:
+		return c.compileInfixExpression(node)
+	case *ast.BreakExpression:
+		c.compileBreakExpression()
+	case *ast.ContinueExpression:
+		c.compileContinueExpression()
+	// Expression Flows
+	case *ast.IfExpression:
+		return c.compileIfExpression(node)
+	case *ast.ForEverExpression:
+		return c.compileForEverExpression(node)
+	case *ast.ForEachArrayOrRangeExpression:
+		return c.compileForEachArrayOrRangeExpression(node)
+	case *ast.ForEachHashExpression:
+		return c.compileForEachHashExpression(node)
+	default:
+		return &UnsupportedError{Node: node}
+	}
+
+	return nil
+}
+
+func (c *Compiler) Bytecode() *Bytecode {
+	// The instructions of function refer to the constants of its program
+	for _, constant := range c.constants {
+		if function, ok := constant.(*object.CompiledFunction); ok {
+			function.Constants = c.constants
+		}
+	}
+
+	return &Bytecode{
+		Instructions: c.currentInstructions(),
+		Positions:    c.scopes[c.scopeIndex].positions,
+		Constants:    c.constants,
+		Globals:      c.symbolTable.Names(),
+	}
+}
+
+// Compile statement functions
+func (c *Compiler) compileStatements(statements []ast.Statement) error {
+	for _, statement := range statements {
+		if err := c.Compile(statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Compiler) compileLetStatement(let *ast.LetStatement) error {
+	// The destructuring pattern is bound by evaluator only
+	if let.Pattern != nil {
+		return &UnsupportedError{Node: let}
+	}
+
+	// Name the function literal for recursive call like: let fib = func(n) { fib(n - 1) }
+	if function, ok := let.Value.(*ast.FunctionLiteralExpression); ok {
+		if err := c.compileFunctionLiteralExpression(function, let.Name.Value); err != nil {
+			return err
+		}
+	} else {
+		if err := c.Compile(let.Value); err != nil {
+			return err
+		}
+	}
+
+	c.storeSymbol(c.symbolTable.Define(let.Name.Value))
+
+	return nil
+}
+
+func (c *Compiler) compileReturnStatement(ret *ast.ReturnStatement) error {
+	if err := c.Compile(ret.ReturnValue); err != nil {
+		return err
+	}
+
+	c.emit(OpReturnValue)
+
+	return nil
+}
+
+func (c *Compiler) compileFunctionStatement(function *ast.FunctionStatement) error {
+	symbol := c.symbolTable.Define(function.Name.Value)
+
+	if err := c.compileFunctionLiteralExpression(function.Function, function.Name.Value); err != nil {
+		return err
+	}
+
+	c.storeSymbol(symbol)
+
+	return nil
+}
+
+func (c *Compiler) compileExpressionStatement(statement *ast.ExpressionStatement) error {
+	// Parser may leave the expression to nil when the expression cannot be parsed
+	if statement.Expression == nil {
+		c.emit(OpNil)
+	} else if err := c.Compile(statement.Expression); err != nil {
+		return err
+	}
+
+	c.emit(OpPop)
+
+	return nil
+}
+
+// Compile the block and keep the value of last statement on the stack like evaluator
+func (c *Compiler) compileBlockValue(block *ast.BlockStatement) error {
+	if len(block.Statements) == 0 {
+		c.emit(OpNil)
+
+		return nil
+	}
+
+	if err := c.compileStatements(block.Statements); err != nil {
+		return err
+	}
+
+	switch statement := block.Statements[len(block.Statements)-1].(type) {
+	case *ast.ExpressionStatement:
+		c.removeLastPop()
+	case *ast.LetStatement:
+		c.loadName(statement.Name.Value)
+	case *ast.FunctionStatement:
+		c.loadName(statement.Name.Value)
+	}
+
+	return nil
+}
+
+// Compile expression functions
+func (c *Compiler) compileIdentifierExpression(identifier *ast.IdentifierExpression) {
+	c.loadName(identifier.Value)
+}
+
+func (c *Compiler) compileAssignExpression(assign *ast.AssignExpression) error {
+	// Read the variable first like evaluator, it reports the error when variable is not found,
+	// the target of index and dot will be read once when assign
+	if identifier, ok := assign.Left.(*ast.IdentifierExpression); ok {
+		c.compileIdentifierExpression(identifier)
+		c.emit(OpPop)
+	}
+
+	if err := c.Compile(assign.Value); err != nil {
+		return err
+	}
+
+	switch left := assign.Left.(type) {
+	case *ast.IdentifierExpression:
+		c.compileAssignIdentifier(left.Value)
+	case *ast.IndexExpression:
+		if err := c.compileOperands(OpSetIndex, left.Left, left.Index); err != nil {
+			return err
+		}
+	case *ast.DotExpression:
+		if err := c.compileOperands(OpSetDot, left.Left, left.Item); err != nil {
+			return err
+		}
+	default:
+		return &UnsupportedError{Node: assign}
+	}
+
+	return nil
+}
+
+func (c *Compiler) compileAssignIdentifier(name string) {
+	symbol, ok := c.symbolTable.Resolve(name)
+
+	switch {
+	// The variable in top level scope
+	case ok == true && symbol.Scope == GlobalScope && c.symbolTable.Outer == nil:
+		c.storeSymbol(symbol)
+	// The variable in current function scope
+	case ok == true && symbol.Scope == LocalScope:
+		c.storeSymbol(symbol)
+	// The variable of outer scope will be shadowed in function scope like evaluator
+	case c.symbolTable.Outer != nil:
+		c.storeSymbol(c.symbolTable.Define(name))
+	// The variable which provided by environment
+	default:
+		c.emit(OpSetName, c.addConstant(&object.String{Value: name}))
+	}
+
+	c.emit(OpNil)
+}
+
+func (c *Compiler) compileTemplateLiteralExpression(template *ast.TemplateLiteralExpression) error {
+	for _, part := range template.Parts {
+		if err := c.Compile(part); err != nil {
+			return err
+		}
+	}
+
+	c.emit(OpTemplate, len(template.Parts))
+
+	return nil
+}
+
+func (c *Compiler) compileArrayLiteralExpression(array *ast.ArrayLiteralExpression) error {
+	for _, element := range array.Elements {
+		if err := c.Compile(element); err != nil {
+			return err
+		}
+	}
+
+	c.emit(OpArray, len(array.Elements))
+
+	return nil
+}
+
+func (c *Compiler) compileHashLiteralExpression(hash *ast.HashLiteralExpression) error {
+	for _, key := range hash.Order {
+		if err := c.Compile(key); err != nil {
+			return err
+		}
+
+		if err := c.Compile(hash.Pairs[key]); err != nil {
+			return err
+		}
+	}
+
+	c.emit(OpHash, len(hash.Order))
+
+	return nil
+}
+
+func (c *Compiler) compileFunctionLiteralExpression(function *ast.FunctionLiteralExpression, name string) error {
+	// The default and variadic parameters are bound by evaluator only
+	if len(function.Defaults) > 0 || function.Rest != nil {
+		return &UnsupportedError{Node: function}
+	}
+
+	c.enterScope()
+
+	if name != "" {
+		c.symbolTable.DefineFunctionName(name)
+	}
+
+	for _, parameter := range function.Parameters {
+		c.symbolTable.Store(c.symbolTable.Define(parameter.Value), false)
+	}
+
+	if err := c.compileBlockValue(function.Block); err != nil {
+		c.leaveScope()
+
+		return err
+	}
+
+	c.emit(OpReturnValue)
+
+	// The closure captures the variables by value, let evaluator run it when the value will be changed
+	if reason := c.symbolTable.CheckClosure(); reason != "" {
+		c.leaveScope()
+
+		return &UnsupportedError{Node: function, Reason: reason}
+	}
+
+	freeSymbols := c.symbolTable.FreeSymbols
+	localNames := c.symbolTable.NamesByIndex()
+	numLocals := c.symbolTable.NumDefinitions()
+	positions := c.scopes[c.scopeIndex].positions
+	instructions := c.leaveScope()
+
+	// Load the captured variables for closure
+	for _, symbol := range freeSymbols {
+		c.loadSymbol(symbol)
+	}
+
+	freeNames := make([]string, len(freeSymbols))
+	for index, symbol := range freeSymbols {
+		freeNames[index] = symbol.Name
+	}
+
+	compiledFunction := &object.CompiledFunction{
+		Instructions:  instructions,
+		NumLocals:     numLocals,
+		LocalNames:    localNames,
+		FreeNames:     freeNames,
+		NumParameters: len(function.Parameters),
+		Positions:     positions,
+		Parameters:    function.Parameters,
+		Block:         function.Block,
+	}
+
+	c.emit(OpClosure, c.addConstant(compiledFunction), len(freeSymbols))
+
+	return nil
+}
+
+func (c *Compiler) compileCallExpression(call *ast.CallExpression) error {
+	if err := c.Compile(call.Function); err != nil {
+		return err
+	}
+
+	for _, argument := range call.Arguments {
+		if err := c.Compile(argument); err != nil {
+			return err
+		}
+	}
+
+	// Keep the callee name for the call stack of error
+	callee := c.addConstant(&object.String{Value: call.Function.String()})
+
+	c.emit(OpCall, len(call.Arguments), callee)
+
+	return nil
+}
+
+func (c *Compiler) compilePrefixExpression(prefix *ast.PrefixExpression) error {
+	operator := indexOf(prefixOperators, prefix.Operator)
+	if operator < 0 {
+		return &UnsupportedError{Node: prefix}
+	}
+
+	if err := c.Compile(prefix.Right); err != nil {
+		return err
+	}
+
+	c.emit(OpPrefix, operator)
+
+	return nil
+}
+
+func (c *Compiler) compileInfixExpression(infix *ast.InfixExpression) error {
+	if infix.Operator == "&&" || infix.Operator == "||" {
+		return c.compileLogicalExpression(infix)
+	}
+
+	operator := indexOf(infixOperators, infix.Operator)
+	if operator < 0 {
+		return &UnsupportedError{Node: infix}
+	}
+
+	if err := c.Compile(infix.Left); err != nil {
+		return err
+	}
+
+	if err := c.Compile(infix.Right); err != nil {
+		return err
+	}
+
+	c.emit(OpInfix, operator)
+
+	return nil
+}
+
+// The right side is skipped when the left side decides the result like evaluator, the left side is kept as result
+func (c *Compiler) compileLogicalExpression(logical *ast.InfixExpression) error {
+	if err := c.Compile(logical.Left); err != nil {
+		return err
+	}
+
+	jump := OpJumpNotTruthyOrPop
+	if logical.Operator == "||" {
+		jump = OpJumpTruthyOrPop
+	}
+
+	jumpPosition := c.emit(jump, 9999)
+
+	if err := c.Compile(logical.Right); err != nil {
+		return err
+	}
+
+	c.changeOperand(jumpPosition, len(c.currentInstructions()))
+
+	return nil
+}
+
+func (c *Compiler) compileBreakExpression() {
+	loop := c.currentLoop()
+
+	// Outside the loop, break is a value like evaluator
+	if loop == nil {
+		c.emit(OpConstant, c.addConstant(BREAK))
+
+		return
+	}
+
+	loop.breaks = append(loop.breaks, c.emit(OpJump, 9999))
+}
+
+func (c *Compiler) compileContinueExpression() {
+	loop := c.currentLoop()
+
+	// Outside the loop, continue is a value like evaluator
+	if loop == nil {
+		c.emit(OpConstant, c.addConstant(CONTINUE))
+
+		return
+	}
+
+	c.emit(OpJump, loop.start)
+}
+
+// Compile expression flow functions
+func (c *Compiler) compileIfExpression(ifExp *ast.IfExpression) error {
+	endJumpPositions := []int{}
+
+	for _, scene := range ifExp.Scenes {
+		if err := c.Compile(scene.Condition); err != nil {
+			return err
+		}
+
+		// Jump to next scene when the condition is not truthy
+		jumpNotTruthyPosition := c.emit(OpJumpNotTruthy, 9999)
+
+		if err := c.compileBlockValue(scene.Block); err != nil {
+			return err
+		}
+
+		endJumpPositions = append(endJumpPositions, c.emit(OpJump, 9999))
+
+		c.changeOperand(jumpNotTruthyPosition, len(c.currentInstructions()))
+	}
+
+	if ifExp.Alternative != nil {
+		if err := c.compileBlockValue(ifExp.Alternative); err != nil {
+			return err
+		}
+	} else {
+		c.emit(OpNil)
+	}
+
+	for _, position := range endJumpPositions {
+		c.changeOperand(position, len(c.currentInstructions()))
+	}
+
+	return nil
+}
+
+func (c *Compiler) compileForEverExpression(forever *ast.ForEverExpression) error {
+	loop := c.enterLoop()
+
+	if err := c.compileStatements(forever.Block.Statements); err != nil {
+		return err
+	}
+
+	c.emit(OpJump, loop.start)
+
+	c.leaveLoop(len(c.currentInstructions()))
+	c.emit(OpNil)
+
+	return nil
+}
+
+func (c *Compiler) compileForEachArrayOrRangeExpression(arrayOrRange *ast.ForEachArrayOrRangeExpression) error {
+	if arrayOrRange.Pattern != nil {
+		return &UnsupportedError{Node: arrayOrRange}
+	}
+
+	if err := c.Compile(arrayOrRange.Iterable); err != nil {
+		return err
+	}
+
+	c.emit(OpIterator, IteratorArray)
+
+	// The index of element is stored to _loopKey like evaluator
+	return c.compileForEachBody(arrayOrRange.Block, arrayOrRange.Value, "_loopKey")
+}
+
+func (c *Compiler) compileForEachHashExpression(hash *ast.ForEachHashExpression) error {
+	if err := c.Compile(hash.Iterable); err != nil {
+		return err
+	}
+
+	c.emit(OpIterator, IteratorHash)
+
+	// OpIterNext pushes key then value, so store the value first
+	return c.compileForEachBody(hash.Block, hash.Value, hash.Key)
+}
+
+// The iterator is kept on the stack until the loop finished,
+// break will jump to the pop instruction to remove it
+func (c *Compiler) compileForEachBody(block *ast.BlockStatement, names ...string) error {
+	loop := c.enterLoop()
+
+	nextPosition := c.emit(OpIterNext, 9999)
+
+	for _, name := range names {
+		c.storeSymbol(c.symbolTable.Define(name))
+	}
+
+	if err := c.compileStatements(block.Statements); err != nil {
+		return err
+	}
+
+	c.emit(OpJump, loop.start)
+
+	c.leaveLoop(len(c.currentInstructions()))
+	c.emit(OpPop)
+
+	c.changeOperand(nextPosition, len(c.currentInstructions()))
+	c.emit(OpNil)
+
+	return nil
+}
+
+// Helper functions
+func (c *Compiler) compileOperands(op Opcode, operands ...ast.Expression) error {
+	for _, operand := range operands {
+		if err := c.Compile(operand); err != nil {
+			return err
+		}
+	}
+
+	c.emit(op)
+
+	return nil
+}
+
+func (c *Compiler) loadName(name string) {
+	if symbol, ok := c.symbolTable.Resolve(name); ok {
+		c.loadSymbol(symbol)
+	} else {
+		// Resolve it in run time from global, environment or built-in functions
+		c.symbolTable.Unresolve(name)
+		c.emit(OpGetName, c.addConstant(&object.String{Value: name}))
+	}
+}
+
+func (c *Compiler) loadSymbol(symbol Symbol) {
+	switch symbol.Scope {
+	case GlobalScope:
+		c.emit(OpGetGlobal, symbol.Index)
+	case LocalScope:
+		c.emit(OpGetLocal, symbol.Index)
+	case FreeScope:
+		c.emit(OpGetFree, symbol.Index)
+	case FunctionScope:
+		c.emit(OpCurrentClosure)
+	}
+}
+
+func (c *Compiler) storeSymbol(symbol Symbol) {
+	c.symbolTable.Store(symbol, c.currentLoop() != nil)
+
+	if symbol.Scope == GlobalScope {
+		c.emit(OpSetGlobal, symbol.Index)
+	} else {
+		c.emit(OpSetLocal, symbol.Index)
+	}
+}
+
+func (c *Compiler) addConstant(obj object.Object) int {
+	c.constants = append(c.constants, obj)
+
+	return len(c.constants) - 1
+}
+
+func (c *Compiler) emit(op Opcode, operands ...int) int {
+	c.checkOperands(op, operands...)
+
+	instruction := Make(op, operands...)
+	position := c.addInstruction(instruction)
+
+	c.setLastInstruction(op, position)
+
+	return position
+}
+
+func (c *Compiler) addInstruction(instruction []byte) int {
+	position := len(c.currentInstructions())
+
+	c.scopes[c.scopeIndex].instructions = append(c.currentInstructions(), instruction...)
+
+	c.addPosition(position)
+
+	return position
+}
+
+// Map the instruction to the start position of compiling node, the same position will be merged
+func (c *Compiler) addPosition(offset int) {
+	if c.node == nil {
+		return
+	}
+
+	scope := &c.scopes[c.scopeIndex]
+	position := c.node.StartPos()
+
+	if length := len(scope.positions); length > 0 && scope.positions[length-1].Position == position {
+		return
+	}
+
+	scope.positions = append(scope.positions, object.SourcePosition{
+		Offset:   offset,
+		Position: position,
+	})
+}
+
+func (c *Compiler) setLastInstruction(op Opcode, position int) {
+	c.scopes[c.scopeIndex].previousInstruction = c.scopes[c.scopeIndex].lastInstruction
+	c.scopes[c.scopeIndex].lastInstruction = emittedInstruction{
+		Opcode:   op,
+		Position: position,
+	}
+}
+
+func (c *Compiler) removeLastPop() {
+	scope := &c.scopes[c.scopeIndex]
+
+	if scope.lastInstruction.Opcode != OpPop {
+		return
+	}
+
+	scope.instructions = scope.instructions[:scope.lastInstruction.Position]
+	scope.lastInstruction = scope.previousInstruction
+
+	// Remove the positions of removed instruction
+	for len(scope.positions) > 0 && scope.positions[len(scope.positions)-1].Offset >= len(scope.instructions) {
+		scope.positions = scope.positions[:len(scope.positions)-1]
+	}
+}
+
+func (c *Compiler) changeOperand(position int, operand int) {
+	op := Opcode(c.currentInstructions()[position])
+	c.checkOperands(op, operand)
+
+	instruction := Make(op, operand)
+
+	copy(c.currentInstructions()[position:], instruction)
+}
+
+// Keep the first overflowed operand, the program will be run by evaluator instead of the wrong instruction
+func (c *Compiler) checkOperands(op Opcode, operands ...int) {
+	if c.overflow != nil || c.node == nil {
+		return
+	}
+
+	if err := checkOperands(op, operands...); err != nil {
+		c.overflow = &UnsupportedError{
+			Node:   c.node,
+			Reason: err.Error(),
+		}
+	}
+}
+
+func (c *Compiler) currentInstructions() Instructions {
+	return c.scopes[c.scopeIndex].instructions
+}
+
+func (c *Compiler) enterScope() {
+	c.scopes = append(c.scopes, compilationScope{
+		instructions: Instructions{},
+	})
+	c.scopeIndex++
+
+	c.symbolTable = NewEnclosedSymbolTable(c.symbolTable)
+}
+
+func (c *Compiler) leaveScope() Instructions {
+	instructions := c.currentInstructions()
+
+	c.scopes = c.scopes[:len(c.scopes)-1]
+	c.scopeIndex--
+
+	c.symbolTable = c.symbolTable.Outer
+
+	return instructions
+}
+
+func (c *Compiler) enterLoop() *loopScope {
+	loop := &loopScope{
+		start:  len(c.currentInstructions()),
+		breaks: []int{},
+	}
+
+	c.scopes[c.scopeIndex].loops = append(c.scopes[c.scopeIndex].loops, loop)
+
+	return loop
+}
+
+func (c *Compiler) leaveLoop(breakTarget int) {
+	loops := c.scopes[c.scopeIndex].loops
+	loop := loops[len(loops)-1]
+
+	for _, position := range loop.breaks {
+		c.changeOperand(position, breakTarget)
+	}
+
+	c.scopes[c.scopeIndex].loops = loops[:len(loops)-1]
+}
+
+func (c *Compiler) currentLoop() *loopScope {
+	loops := c.scopes[c.scopeIndex].loops
+
+	if len(loops) == 0 {
+		return nil
+	}
+
+	return loops[len(loops)-1]
+}
@@ -0,0 +1,441 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/zeuxisoo/go-skrip/lexer"
+	"github.com/zeuxisoo/go-skrip/object"
+	"github.com/zeuxisoo/go-skrip/parser"
+)
+
+type expectedCompile struct {
+	source       string
+	constants    []interface{}
+	instructions [][]byte
+}
+
+// Test case
+func TestMake(t *testing.T) {
+	Convey("Make instruction test", t, func() {
+		expecteds := []struct {
+			op       Opcode
+			operands []int
+			result   []byte
+		}{
+			{OpConstant, []int{65534}, []byte{byte(OpConstant), 255, 254}},
+			{OpInfix, []int{1}, []byte{byte(OpInfix), 1}},
+			{OpPop, []int{}, []byte{byte(OpPop)}},
+			{OpCall, []int{2, 258}, []byte{byte(OpCall), 2, 1, 2}},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Opcode: %d", index, expected.op), func() {
+				instruction := Make(expected.op, expected.operands...)
+
+				So(instruction, ShouldResemble, expected.result)
+
+				definition, err := Lookup(byte(expected.op))
+				So(err, ShouldBeNil)
+
+				operands, read := ReadOperands(definition, instruction[1:])
+				So(operands, ShouldResemble, expected.operands)
+				So(read, ShouldEqual, len(instruction)-1)
+			})
+		}
+	})
+}
+
+func TestInstructionsString(t *testing.T) {
+	Convey("Instructions string test", t, func() {
+		instructions := concatInstructions([][]byte{
+			Make(OpConstant, 1),
+			Make(OpInfix, 0),
+			Make(OpClosure, 65535, 255),
+			Make(OpPop),
+		})
+
+		expected := "0000 OpConstant 1\n0003 OpInfix 0\n0005 OpClosure 65535 255\n0009 OpPop\n"
+
+		So(instructions.String(), ShouldEqual, expected)
+	})
+}
+
+func TestSymbolTable(t *testing.T) {
+	Convey("Symbol table test", t, func() {
+		global := NewSymbolTable()
+		global.Define("a")
+
+		local := NewEnclosedSymbolTable(global)
+		local.Define("b")
+
+		nested := NewEnclosedSymbolTable(local)
+		nested.Define("c")
+
+		Convey("Resolve global symbol", func() {
+			symbol, ok := nested.Resolve("a")
+
+			So(ok, ShouldBeTrue)
+			So(symbol, ShouldResemble, Symbol{Name: "a", Scope: GlobalScope, Index: 0})
+		})
+
+		Convey("Resolve local symbol", func() {
+			symbol, ok := nested.Resolve("c")
+
+			So(ok, ShouldBeTrue)
+			So(symbol, ShouldResemble, Symbol{Name: "c", Scope: LocalScope, Index: 0})
+		})
+
+		Convey("Resolve outer local symbol as free symbol", func() {
+			symbol, ok := nested.Resolve("b")
+
+			So(ok, ShouldBeTrue)
+			So(symbol, ShouldResemble, Symbol{Name: "b", Scope: FreeScope, Index: 0})
+			So(nested.FreeSymbols, ShouldResemble, []Symbol{{Name: "b", Scope: LocalScope, Index: 0}})
+		})
+
+		Convey("Redefine the symbol will reuse the index", func() {
+			So(global.Define("a").Index, ShouldEqual, 0)
+			So(global.Define("d").Index, ShouldEqual, 1)
+		})
+
+		Convey("Unknown symbol cannot be resolved", func() {
+			_, ok := nested.Resolve("x")
+
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
+
+func TestCompileExpression(t *testing.T) {
+	Convey("Compile expression test", t, func() {
+		expecteds := []expectedCompile{
+			{
+				"1 + 2",
+				[]interface{}{1, 2},
+				[][]byte{
+					Make(OpConstant, 0),
+					Make(OpConstant, 1),
+					Make(OpInfix, 0),
+					Make(OpPop),
+				},
+			},
+			{
+				"-1.5",
+				[]interface{}{1.5},
+				[][]byte{
+					Make(OpConstant, 0),
+					Make(OpPrefix, 1),
+					Make(OpPop),
+				},
+			},
+			{
+				`let a = "foo"; a`,
+				[]interface{}{"foo"},
+				[][]byte{
+					Make(OpConstant, 0),
+					Make(OpSetGlobal, 0),
+					Make(OpGetGlobal, 0),
+					Make(OpPop),
+				},
+			},
+			{
+				`print`,
+				[]interface{}{"print"},
+				[][]byte{
+					Make(OpGetName, 0),
+					Make(OpPop),
+				},
+			},
+			{
+				`[1, true][0]`,
+				[]interface{}{1, 0},
+				[][]byte{
+					Make(OpConstant, 0),
+					Make(OpTrue),
+					Make(OpArray, 2),
+					Make(OpConstant, 1),
+					Make(OpIndex),
+					Make(OpPop),
+				},
+			},
+			{
+				`1 && 2 || 3`,
+				[]interface{}{1, 2, 3},
+				[][]byte{
+					Make(OpConstant, 0),
+					Make(OpJumpNotTruthyOrPop, 9),
+					Make(OpConstant, 1),
+					Make(OpJumpTruthyOrPop, 15),
+					Make(OpConstant, 2),
+					Make(OpPop),
+				},
+			},
+			{
+				`"a${1}"`,
+				[]interface{}{"a", 1},
+				[][]byte{
+					Make(OpConstant, 0),
+					Make(OpConstant, 1),
+					Make(OpTemplate, 2),
+					Make(OpPop),
+				},
+			},
+			{
+				`if (true) { 1 } else { nil }`,
+				[]interface{}{1},
+				[][]byte{
+					Make(OpTrue),
+					Make(OpJumpNotTruthy, 10),
+					Make(OpConstant, 0),
+					Make(OpJump, 11),
+					Make(OpNil),
+					Make(OpPop),
+				},
+			},
+		}
+
+		testCompile(expecteds)
+	})
+}
+
+func TestCompileLoop(t *testing.T) {
+	Convey("Compile loop test", t, func() {
+		expecteds := []expectedCompile{
+			{
+				`for { break; continue; }`,
+				[]interface{}{},
+				[][]byte{
+					Make(OpJump, 11),
+					Make(OpPop),
+					Make(OpJump, 0),
+					Make(OpPop),
+					Make(OpJump, 0),
+					Make(OpNil),
+					Make(OpPop),
+				},
+			},
+			{
+				`for v in [] { break; }`,
+				[]interface{}{},
+				[][]byte{
+					Make(OpArray, 0),
+					Make(OpIterator, IteratorArray),
+					Make(OpIterNext, 22),
+					Make(OpSetGlobal, 0),
+					Make(OpSetGlobal, 1),
+					Make(OpJump, 21),
+					Make(OpPop),
+					Make(OpJump, 5),
+					Make(OpPop),
+					Make(OpNil),
+					Make(OpPop),
+				},
+			},
+		}
+
+		testCompile(expecteds)
+	})
+}
+
+func TestCompileFunction(t *testing.T) {
+	Convey("Compile function test", t, func() {
+		source := `func add(a) { func(b) { a + b } }`
+
+		theCompiler := testCompileSource(source)
+		bytecode := theCompiler.Bytecode()
+
+		Convey("Outer function should capture nothing", func() {
+			So(bytecode.Instructions.String(), ShouldEqual, concatInstructions([][]byte{
+				Make(OpClosure, 1, 0),
+				Make(OpSetGlobal, 0),
+			}).String())
+		})
+
+		Convey("Inner function should capture the parameter of outer function", func() {
+			outer := bytecode.Constants[1].(*object.CompiledFunction)
+
+			So(outer.NumParameters, ShouldEqual, 1)
+			So(Instructions(outer.Instructions).String(), ShouldEqual, concatInstructions([][]byte{
+				Make(OpGetLocal, 0),
+				Make(OpClosure, 0, 1),
+				Make(OpReturnValue),
+			}).String())
+
+			inner := bytecode.Constants[0].(*object.CompiledFunction)
+
+			So(Instructions(inner.Instructions).String(), ShouldEqual, concatInstructions([][]byte{
+				Make(OpGetFree, 0),
+				Make(OpGetLocal, 0),
+				Make(OpInfix, 0),
+				Make(OpReturnValue),
+			}).String())
+		})
+
+		Convey("Global names should be recorded", func() {
+			So(bytecode.Globals, ShouldResemble, map[string]int{"add": 0})
+		})
+	})
+}
+
+func TestCompilePosition(t *testing.T) {
+	Convey("Compile position test", t, func() {
+		theCompiler := testCompileSource("let a = 1;\n  a + b")
+		bytecode := theCompiler.Bytecode()
+
+		function := &object.CompiledFunction{
+			Instructions: bytecode.Instructions,
+			Positions:    bytecode.Positions,
+		}
+
+		expecteds := []struct {
+			offset   int
+			position string
+		}{
+			{0, "1:9"},  // OpConstant 1
+			{3, "1:1"},  // OpSetGlobal a
+			{6, "2:3"},  // OpGetGlobal a
+			{9, "2:7"},  // OpGetName b
+			{12, "2:3"}, // OpInfix +
+			{14, "2:3"}, // OpPop
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Offset: %d", index, expected.offset), func() {
+				So(function.PositionAt(expected.offset).String(), ShouldEqual, expected.position)
+			})
+		}
+	})
+}
+
+func TestCompileUnsupportedNode(t *testing.T) {
+	Convey("Compile unsupported node test", t, func() {
+		sources := []string{
+			`import lib`,
+			`func(a, b = 2) { a + b }`,
+			`func(...rest) { rest }`,
+			`let f = func(a) { a }; f(a: 1)`,
+			`let [a, b] = [1, 2]`,
+			`for [a] in [[1]] { a }`,
+			`match 1 { _ => 1 }`,
+			`struct P { x }`,
+			`func() { let x = 1; let f = func() { x }; x = 2 }`,
+			`func() { for i in [1] { func() { i } } }`,
+			`func() { let f = func() { y }; let y = 1 }`,
+		}
+
+		for index, source := range sources {
+			Convey(runMessage("Running: %d, Source: %s", index, source), func() {
+				theCompiler := NewCompiler()
+
+				err := theCompiler.Compile(parser.NewParser(lexer.NewLexer(source)).Parse())
+
+				_, ok := err.(*UnsupportedError)
+				So(ok, ShouldBeTrue)
+			})
+		}
+	})
+}
+
+func TestCompileOperandOverflow(t *testing.T) {
+	Convey("Compile operand overflow test", t, func() {
+		expecteds := []struct {
+			name      string
+			source    string
+			supported bool
+		}{
+			{"256 locals", repeatSource("func() { ", "let v%d = nil; ", 256, "}"), true},
+			{"257 locals", repeatSource("func() { ", "let v%d = nil; ", 257, "}"), false},
+			{"255 arguments", repeatSource("f(nil", ", nil", 254, ")"), true},
+			{"256 arguments", repeatSource("f(nil", ", nil", 255, ")"), false},
+			{"65536 globals", repeatSource("", "let g%d = nil;\n", 65536, ""), true},
+			{"65537 globals", repeatSource("", "let g%d = nil;\n", 65537, ""), false},
+			{"65536 constants", repeatSource("", "%d;\n", 65536, ""), true},
+			{"65537 constants", repeatSource("", "%d;\n", 65537, ""), false},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Name: %s", index, expected.name), func() {
+				theParser := parser.NewParser(lexer.NewLexer(expected.source))
+				theProgram := theParser.Parse()
+
+				So(theParser.Errors(), ShouldBeEmpty)
+
+				err := NewCompiler().Compile(theProgram)
+
+				if expected.supported == true {
+					So(err, ShouldBeNil)
+				} else {
+					_, ok := err.(*UnsupportedError)
+					So(ok, ShouldBeTrue)
+					So(err.Error(), ShouldContainSubstring, "exceeds the maximum")
+				}
+			})
+		}
+	})
+}
+
+// Sub method for test case
+func testCompile(expecteds []expectedCompile) {
+	for index, expected := range expecteds {
+		Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+			theCompiler := testCompileSource(expected.source)
+			bytecode := theCompiler.Bytecode()
+
+			So(bytecode.Instructions.String(), ShouldEqual, concatInstructions(expected.instructions).String())
+
+			So(len(bytecode.Constants), ShouldEqual, len(expected.constants))
+			for constantIndex, constant := range expected.constants {
+				So(bytecode.Constants[constantIndex].Inspect(), ShouldEqual, fmt.Sprintf("%v", constant))
+			}
+		})
+	}
+}
+
+func testCompileSource(source string) *Compiler {
+	theParser := parser.NewParser(lexer.NewLexer(source))
+	theProgram := theParser.Parse()
+
+	So(theParser.Errors(), ShouldBeEmpty)
+
+	theCompiler := NewCompiler()
+	So(theCompiler.Compile(theProgram), ShouldBeNil)
+
+	return theCompiler
+}
+
+func concatInstructions(instructions [][]byte) Instructions {
+	out := Instructions{}
+
+	for _, instruction := range instructions {
+		out = append(out, instruction...)
+	}
+
+	return out
+}
+
+// Helper functions for common
+func repeatSource(prefix string, format string, count int, suffix string) string {
+	var out strings.Builder
+
+	out.WriteString(prefix)
+
+	for index := 0; index < count; index++ {
+		if strings.Contains(format, "%d") == true {
+			out.WriteString(fmt.Sprintf(format, index))
+		} else {
+			out.WriteString(format)
+		}
+	}
+
+	out.WriteString(suffix)
+
+	return out.String()
+}
+
+func runMessage(format string, values ...interface{}) string {
+	return fmt.Sprintf(format, values...)
+}
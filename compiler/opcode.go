@@ -0,0 +1,255 @@
+package compiler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Instructions is the encoded bytecode, each instruction is an opcode followed by its operands
+type Instructions []byte
+
+// Opcode is the first byte of each instruction
+type Opcode byte
+
+const (
+	OpConstant Opcode = iota // push constant[operand]
+	OpPop                    // pop the top of stack
+	OpTrue                   // push true
+	OpFalse                  // push false
+	OpNil                    // push nil
+
+	OpInfix  // pop right, left and push left operator[operand] right
+	OpPrefix // pop right and push operator[operand] right
+
+	OpJump               // jump to operand
+	OpJumpNotTruthy      // pop condition and jump to operand when it is not truthy
+	OpJumpTruthyOrPop    // jump to operand when the top of stack is truthy, otherwise pop it, e.g. a || b
+	OpJumpNotTruthyOrPop // jump to operand when the top of stack is not truthy, otherwise pop it, e.g. a && b
+	OpGetGlobal          // push globals[operand]
+	OpSetGlobal          // pop value into globals[operand]
+	OpGetLocal           // push locals[operand]
+	OpSetLocal           // pop value into locals[operand]
+	OpGetFree            // push free variables[operand] of current closure
+	OpCurrentClosure     // push current closure for recursive call
+	OpGetName            // push the value of name constant[operand] from environment or built-in functions
+	OpSetName            // pop value into environment by name constant[operand]
+
+	OpArray    // pop operand elements and push array
+	OpHash     // pop operand key/value pairs and push hash
+	OpIndex    // pop index, left and push left[index]
+	OpDot      // pop item, left and push left.item
+	OpRange    // pop end, start and push start..end
+	OpSetIndex // pop index, left, value and set left[index] = value
+	OpSetDot   // pop item, left, value and set left.item = value
+	OpTemplate // pop operand parts and push the concatenated string
+
+	OpCall        // call the function with operand arguments, second operand is the callee name constant
+	OpReturnValue // return the top of stack from current function
+	OpReturn      // return nil from current function
+	OpClosure     // push the closure of compiled function constant[operand] with second operand free variables
+
+	OpIterator // pop iterable and push the iterator of it, operand is the iterator kind
+	OpIterNext // push next value (or key and value) of the iterator, otherwise pop it and jump to operand
+)
+
+// Operators are encoded as the index of the operator tables in instruction
+var infixOperators = []string{"+", "-", "*", "/", "<", ">", "<=", ">=", "==", "!="}
+var prefixOperators = []string{"!", "-", "+"}
+
+// Iterator kinds of OpIterator
+const (
+	IteratorArray = iota // for value in array
+	IteratorHash         // for key, value in hash
+)
+
+// Definition describes the name and operand widths (in bytes) of opcode
+type Definition struct {
+	Name          string
+	OperandWidths []int
+}
+
+var definitions = map[Opcode]*Definition{
+	OpConstant: {"OpConstant", []int{2}},
+	OpPop:      {"OpPop", []int{}},
+	OpTrue:     {"OpTrue", []int{}},
+	OpFalse:    {"OpFalse", []int{}},
+	OpNil:      {"OpNil", []int{}},
+
+	OpInfix:  {"OpInfix", []int{1}},
+	OpPrefix: {"OpPrefix", []int{1}},
+
+	OpJump:               {"OpJump", []int{2}},
+	OpJumpNotTruthy:      {"OpJumpNotTruthy", []int{2}},
+	OpJumpTruthyOrPop:    {"OpJumpTruthyOrPop", []int{2}},
+	OpJumpNotTruthyOrPop: {"OpJumpNotTruthyOrPop", []int{2}},
+	OpGetGlobal:          {"OpGetGlobal", []int{2}},
+	OpSetGlobal:          {"OpSetGlobal", []int{2}},
+	OpGetLocal:           {"OpGetLocal", []int{1}},
+	OpSetLocal:           {"OpSetLocal", []int{1}},
+	OpGetFree:            {"OpGetFree", []int{1}},
+	OpCurrentClosure:     {"OpCurrentClosure", []int{}},
+	OpGetName:            {"OpGetName", []int{2}},
+	OpSetName:            {"OpSetName", []int{2}},
+
+	OpArray:    {"OpArray", []int{2}},
+	OpHash:     {"OpHash", []int{2}},
+	OpIndex:    {"OpIndex", []int{}},
+	OpDot:      {"OpDot", []int{}},
+	OpRange:    {"OpRange", []int{}},
+	OpSetIndex: {"OpSetIndex", []int{}},
+	OpSetDot:   {"OpSetDot", []int{}},
+	OpTemplate: {"OpTemplate", []int{2}},
+
+	OpCall:        {"OpCall", []int{1, 2}},
+	OpReturnValue: {"OpReturnValue", []int{}},
+	OpReturn:      {"OpReturn", []int{}},
+	OpClosure:     {"OpClosure", []int{2, 1}},
+
+	OpIterator: {"OpIterator", []int{1}},
+	OpIterNext: {"OpIterNext", []int{2}},
+}
+
+// Lookup returns the definition of opcode
+func Lookup(op byte) (*Definition, error) {
+	definition, ok := definitions[Opcode(op)]
+	if ok == false {
+		return nil, fmt.Errorf("opcode %d undefined", op)
+	}
+
+	return definition, nil
+}
+
+// Make encodes the opcode and operands to instruction
+// Returns the error when the operand cannot be encoded by its width, e.g. the 256th local variable for 1 byte
+func checkOperands(op Opcode, operands ...int) error {
+	definition, ok := definitions[op]
+	if ok == false {
+		return fmt.Errorf("opcode %d undefined", op)
+	}
+
+	for index, operand := range operands {
+		maximum := 1<<(8*uint(definition.OperandWidths[index])) - 1
+
+		if operand < 0 || operand > maximum {
+			return fmt.Errorf("operand %d of %s exceeds the maximum %d", operand, definition.Name, maximum)
+		}
+	}
+
+	return nil
+}
+
+func Make(op Opcode, operands ...int) []byte {
+	definition, ok := definitions[op]
+	if ok == false {
+		return []byte{}
+	}
+
+	instructionLength := 1
+	for _, width := range definition.OperandWidths {
+		instructionLength += width
+	}
+
+	instruction := make([]byte, instructionLength)
+	instruction[0] = byte(op)
+
+	offset := 1
+	for index, operand := range operands {
+		width := definition.OperandWidths[index]
+
+		switch width {
+		case 2:
+			binary.BigEndian.PutUint16(instruction[offset:], uint16(operand))
+		case 1:
+			instruction[offset] = byte(operand)
+		}
+
+		offset += width
+	}
+
+	return instruction
+}
+
+// ReadOperands decodes the operands of instruction by definition, it returns the operands and read bytes
+func ReadOperands(definition *Definition, instructions Instructions) ([]int, int) {
+	operands := make([]int, len(definition.OperandWidths))
+	offset := 0
+
+	for index, width := range definition.OperandWidths {
+		switch width {
+		case 2:
+			operands[index] = int(ReadUint16(instructions[offset:]))
+		case 1:
+			operands[index] = int(ReadUint8(instructions[offset:]))
+		}
+
+		offset += width
+	}
+
+	return operands, offset
+}
+
+func ReadUint16(instructions Instructions) uint16 {
+	return binary.BigEndian.Uint16(instructions)
+}
+
+func ReadUint8(instructions Instructions) uint8 {
+	return uint8(instructions[0])
+}
+
+// InfixOperator returns the operator of OpInfix operand
+func InfixOperator(operand int) string {
+	return infixOperators[operand]
+}
+
+// PrefixOperator returns the operator of OpPrefix operand
+func PrefixOperator(operand int) string {
+	return prefixOperators[operand]
+}
+
+// String returns the disassembled instructions like "0000 OpConstant 1"
+func (ins Instructions) String() string {
+	var out bytes.Buffer
+
+	position := 0
+	for position < len(ins) {
+		definition, err := Lookup(ins[position])
+		if err != nil {
+			fmt.Fprintf(&out, "ERROR: %s\n", err)
+
+			position++
+			continue
+		}
+
+		operands, read := ReadOperands(definition, ins[position+1:])
+
+		fmt.Fprintf(&out, "%04d %s\n", position, ins.formatInstruction(definition, operands))
+
+		position += 1 + read
+	}
+
+	return out.String()
+}
+
+func (ins Instructions) formatInstruction(definition *Definition, operands []int) string {
+	switch len(definition.OperandWidths) {
+	case 0:
+		return definition.Name
+	case 1:
+		return fmt.Sprintf("%s %d", definition.Name, operands[0])
+	case 2:
+		return fmt.Sprintf("%s %d %d", definition.Name, operands[0], operands[1])
+	}
+
+	return fmt.Sprintf("ERROR: unhandled operand count for %s", definition.Name)
+}
+
+func indexOf(items []string, item string) int {
+	for index, value := range items {
+		if value == item {
+			return index
+		}
+	}
+
+	return -1
+}
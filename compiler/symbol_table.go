@@ -0,0 +1,204 @@
+package compiler
+
+import (
+	"fmt"
+)
+
+// SymbolScope is where the variable stored in vm
+type SymbolScope string
+
+const (
+	GlobalScope   SymbolScope = "GLOBAL"
+	LocalScope    SymbolScope = "LOCAL"
+	FreeScope     SymbolScope = "FREE"
+	FunctionScope SymbolScope = "FUNCTION"
+)
+
+type Symbol struct {
+	Name  string
+	Scope SymbolScope
+	Index int
+}
+
+type SymbolTable struct {
+	Outer *SymbolTable
+
+	store          map[string]Symbol
+	numDefinitions int
+
+	FreeSymbols []Symbol
+
+	// The free variables are captured by value, so the closure is only compiled when its
+	// captured local variables will not be changed, and all names are defined before used
+	captured   map[int]bool    // the local symbols captured by inner functions
+	stores     map[int]int     // the number of stores of local symbols
+	loopStores map[int]bool    // the local symbols stored in loop
+	unresolved map[string]bool // the names resolved in run time by this and inner functions
+}
+
+func NewSymbolTable() *SymbolTable {
+	return &SymbolTable{
+		store:       make(map[string]Symbol),
+		FreeSymbols: []Symbol{},
+		captured:    make(map[int]bool),
+		stores:      make(map[int]int),
+		loopStores:  make(map[int]bool),
+		unresolved:  make(map[string]bool),
+	}
+}
+
+func NewEnclosedSymbolTable(outer *SymbolTable) *SymbolTable {
+	symbolTable := NewSymbolTable()
+	symbolTable.Outer = outer
+
+	return symbolTable
+}
+
+// Define returns the symbol of name in current table, the existing global or local symbol will be reused
+func (s *SymbolTable) Define(name string) Symbol {
+	if symbol, ok := s.store[name]; ok && (symbol.Scope == GlobalScope || symbol.Scope == LocalScope) {
+		return symbol
+	}
+
+	symbol := Symbol{
+		Name:  name,
+		Index: s.numDefinitions,
+	}
+
+	if s.Outer == nil {
+		symbol.Scope = GlobalScope
+	} else {
+		symbol.Scope = LocalScope
+	}
+
+	s.store[name] = symbol
+	s.numDefinitions++
+
+	return symbol
+}
+
+// DefineFunctionName defines the name of current function for recursive call
+func (s *SymbolTable) DefineFunctionName(name string) Symbol {
+	symbol := Symbol{
+		Name:  name,
+		Scope: FunctionScope,
+		Index: 0,
+	}
+
+	s.store[name] = symbol
+
+	return symbol
+}
+
+// Resolve finds the symbol from current table to outer tables,
+// the local symbol of outer function will be captured as free symbol
+func (s *SymbolTable) Resolve(name string) (Symbol, bool) {
+	symbol, ok := s.store[name]
+	if ok == true || s.Outer == nil {
+		return symbol, ok
+	}
+
+	symbol, ok = s.Outer.Resolve(name)
+	if ok == false {
+		return symbol, ok
+	}
+
+	if symbol.Scope == GlobalScope {
+		return symbol, ok
+	}
+
+	if symbol.Scope == LocalScope {
+		s.Outer.captured[symbol.Index] = true
+	}
+
+	return s.defineFree(symbol), true
+}
+
+// NamesByIndex returns the defined global or local symbol names by index
+func (s *SymbolTable) NamesByIndex() []string {
+	names := make([]string, s.numDefinitions)
+
+	for name, index := range s.Names() {
+		names[index] = name
+	}
+
+	return names
+}
+
+// NumDefinitions returns the count of defined global or local symbols
+func (s *SymbolTable) NumDefinitions() int {
+	return s.numDefinitions
+}
+
+// Names returns the defined symbols by name
+func (s *SymbolTable) Names() map[string]int {
+	names := make(map[string]int)
+
+	for name, symbol := range s.store {
+		if symbol.Scope == GlobalScope || symbol.Scope == LocalScope {
+			names[name] = symbol.Index
+		}
+	}
+
+	return names
+}
+
+// Store records the local symbol is set, the parameters are stored when the function called
+func (s *SymbolTable) Store(symbol Symbol, inLoop bool) {
+	if symbol.Scope != LocalScope {
+		return
+	}
+
+	s.stores[symbol.Index]++
+
+	if inLoop == true {
+		s.loopStores[symbol.Index] = true
+	}
+}
+
+// Unresolve records the name which cannot be resolved in compile time
+func (s *SymbolTable) Unresolve(name string) {
+	if s.Outer != nil {
+		s.unresolved[name] = true
+	}
+}
+
+// CheckClosure reports the reason when the variables of function cannot be resolved like evaluator,
+// it should be called after the function compiled
+func (s *SymbolTable) CheckClosure() string {
+	for name := range s.unresolved {
+		// Defined after used, e.g. func() { let f = func() { y }; let y = 1; f() }
+		if symbol, ok := s.store[name]; ok && symbol.Scope == LocalScope {
+			return fmt.Sprintf("%s is used before defined", name)
+		}
+
+		s.Outer.Unresolve(name)
+	}
+
+	for name, symbol := range s.store {
+		if symbol.Scope != LocalScope || s.captured[symbol.Index] == false {
+			continue
+		}
+
+		// Changed after captured, e.g. func() { let x = 1; let f = func() { x }; x = 2; f() }
+		if s.stores[symbol.Index] > 1 || s.loopStores[symbol.Index] == true {
+			return fmt.Sprintf("captured variable %s is changed", name)
+		}
+	}
+
+	return ""
+}
+
+func (s *SymbolTable) defineFree(original Symbol) Symbol {
+	s.FreeSymbols = append(s.FreeSymbols, original)
+
+	symbol := Symbol{
+		Name:  original.Name,
+		Scope: FreeScope,
+		Index: len(s.FreeSymbols) - 1,
+	}
+
+	s.store[original.Name] = symbol
+
+	return symbol
+}
@@ -0,0 +1,35 @@
+package skrip
+
+import (
+	"strings"
+
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// ParseError contains the syntax errors of script
+type ParseError struct {
+	Messages []string
+}
+
+func (e *ParseError) Error() string {
+	return strings.Join(e.Messages, "\n")
+}
+
+// RuntimeError is the uncaught error of script, it includes the error raised by exit function
+type RuntimeError struct {
+	Err *object.Error
+}
+
+func (e *RuntimeError) Error() string {
+	return e.Err.Kind + ": " + e.Err.Message
+}
+
+// ExitCode returns the code of exit function, it is false when the error is not raised by exit function
+func (e *RuntimeError) ExitCode() (int, bool) {
+	return e.Err.ExitCode()
+}
+
+// StackTrace returns the error message with the calling functions
+func (e *RuntimeError) StackTrace() string {
+	return e.Err.StackTrace()
+}
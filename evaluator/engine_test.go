@@ -0,0 +1,118 @@
+package evaluator_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/zeuxisoo/go-skrip/ast"
+	. "github.com/zeuxisoo/go-skrip/evaluator"
+	"github.com/zeuxisoo/go-skrip/object"
+	"github.com/zeuxisoo/go-skrip/vm"
+)
+
+type engine func(node ast.Node, env *object.Environment) object.Object
+
+// All test cases will be run by each engine, so the evaluator and vm must have the same behaviour
+var engines = []struct {
+	name string
+	eval engine
+}{
+	{"evaluator", evalByEvaluator},
+	{"vm", evalByVM},
+}
+
+var (
+	testEngine     engine
+	testEngineName string
+	executedEngine string // the engine which actually executed the last program
+	numPrograms    int
+	numFallbacks   int // the programs which cannot be compiled and run by evaluator in vm engine
+)
+
+func TestMain(m *testing.M) {
+	for _, theEngine := range engines {
+		fmt.Printf("Engine: %s\n", theEngine.name)
+
+		testEngine = theEngine.eval
+		testEngineName = theEngine.name
+		numPrograms, numFallbacks = 0, 0
+
+		code := m.Run()
+
+		if numFallbacks > 0 {
+			fmt.Printf("Engine: %s, %d of %d programs fell back to evaluator\n", theEngine.name, numFallbacks, numPrograms)
+		}
+
+		if code != 0 {
+			os.Exit(code)
+		}
+	}
+
+	os.Exit(0)
+}
+
+func TestEngineFallback(t *testing.T) {
+	Convey("Engine fallback test", t, func() {
+		expecteds := []struct {
+			source   string
+			bytecode bool
+		}{
+			{`1 + 2`, true},
+			{`let a = [1, {"b": 2}]; a[1].b`, true},
+			{`func f(n) { if (n < 2) { n } else { f(n - 1) + f(n - 2) } }; f(10)`, true},
+			{`let a = 0; for i in 1..3 { a = a + i }; a`, true},
+			{`let a = nil; "${a || 1}"`, true},
+			{`"abc".upper()`, true},
+			{`func g(x) { func() { x } }; g(1)()`, true},
+			{`func g() { let x = 1; let f = func() { x }; x = 2; f() }; g()`, false},
+			{`func g() { let f = func() { y }; let y = 1; f() }; g()`, false},
+			{`import "missing.sk"`, false},
+			{`try { 1 } catch (e) { 2 }`, false},
+			{`throw "error"`, false},
+			{`match 1 { _ => 1 }`, false},
+			{`struct P { x }`, false},
+			{`let [a] = [1]`, false},
+			{`func f(a = 1) { a }`, false},
+			{`func f(...a) { a }`, false},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				testEval(expected.source)
+
+				// The vm engine runs the whole program by evaluator when any node of it cannot be compiled
+				engine := "evaluator"
+				if testEngineName == "vm" && expected.bytecode == true {
+					engine = "vm"
+				}
+
+				So(executedEngine, ShouldEqual, engine)
+			})
+		}
+	})
+}
+
+func evalByEvaluator(node ast.Node, env *object.Environment) object.Object {
+	executedEngine = "evaluator"
+	numPrograms++
+
+	return Eval(node, env)
+}
+
+func evalByVM(node ast.Node, env *object.Environment) object.Object {
+	executedEngine = "vm"
+	numPrograms++
+
+	result, err := vm.EvalBytecode(node, env)
+	if err != nil {
+		executedEngine = "evaluator"
+		numFallbacks++
+
+		return Eval(node, env)
+	}
+
+	return result
+}
@@ -2,11 +2,19 @@ package evaluator
 
 import (
 	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/zeuxisoo/go-skrip/ast"
 	"github.com/zeuxisoo/go-skrip/builtins"
+	"github.com/zeuxisoo/go-skrip/lexer"
 	"github.com/zeuxisoo/go-skrip/object"
+	"github.com/zeuxisoo/go-skrip/parser"
 )
 
 var (
@@ -18,6 +26,16 @@ var (
 )
 
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	// Stop the program when it is cancelled or the steps exceeded
+	if err := env.Step(); err != nil {
+		return locateError(err, node)
+	}
+
+	// The error will be located by the innermost node which raised it
+	return locateError(evalNode(node, env), node)
+}
+
+func evalNode(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
@@ -28,6 +46,12 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalReturnStatement(node, env)
 	case *ast.FunctionStatement:
 		return evalFunctionStatement(node, env)
+	case *ast.StructStatement:
+		return evalStructStatement(node, env)
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+	case *ast.ThrowStatement:
+		return evalThrowStatement(node, env)
 	case *ast.ExpressionStatement:
 		return Eval(node.Expression, env)
 	case *ast.BlockStatement:
@@ -39,6 +63,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalFloatLiteralExpression(node, env)
 	case *ast.StringLiteralExpression:
 		return evalStringLiteralExpression(node, env)
+	case *ast.TemplateLiteralExpression:
+		return evalTemplateLiteralExpression(node, env)
 	case *ast.NilLiteralExpression:
 		return evalNilLiteralExpression(node, env)
 	case *ast.IdentifierExpression:
@@ -64,6 +90,10 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.PrefixExpression:
 		return evalPrefixExpression(node, env)
 	case *ast.InfixExpression:
+		if node.Operator == "&&" || node.Operator == "||" {
+			return evalLogicalExpression(node, env)
+		}
+
 		left := Eval(node.Left, env)
 		if isError(left) == true {
 			return left
@@ -82,33 +112,60 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	// Expression Flows
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
 	case *ast.ForEverExpression:
 		return evalForEverExpression(node, env)
 	case *ast.ForEachArrayOrRangeExpression:
 		return evalForEachArrayOrRangeExpression(node, env)
 	case *ast.ForEachHashExpression:
 		return evalForEachHashExpression(node, env)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
 	}
 
 	return NIL
 }
 
-func RegisterBuiltIn(name string, function object.BuiltInFunction) {
-	builtins.BuiltIns[name] = &object.BuiltIn{
-		Function: function,
+// FindBuiltIn returns the built-in function which registered to the program first, then the default one
+func FindBuiltIn(name string, env *object.Environment) (*object.BuiltIn, bool) {
+	if builtIn, ok := env.BuiltIn(name); ok {
+		return builtIn, ok
+	}
+
+	builtIn, ok := builtins.BuiltIns[name]
+
+	return builtIn, ok
+}
+
+// FindMethod returns the method of object type which registered to the program first, then the default one
+func FindMethod(objectType object.ObjectType, name string, env *object.Environment) (*object.BuiltIn, bool) {
+	if method, ok := env.Method(objectType, name); ok {
+		return method, ok
 	}
+
+	method, ok := builtins.Methods[objectType][name]
+
+	return method, ok
 }
 
 // Eval function
 func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	var result object.Object
 
+	// The built-in function calls back the function by evaluator, restore the caller for the engine which run this program
+	previousCaller := env.Caller()
+	env.SetCaller(CallFunction)
+	defer env.SetCaller(previousCaller)
+
 	for _, statement := range program.Statements {
 		result = Eval(statement, env)
 
 		switch result := result.(type) {
 		case *object.ReturnValue:
 			return result.Value
+		case *object.Error:
+			return result
 		}
 	}
 
@@ -121,6 +178,14 @@ func evalLetStatement(let *ast.LetStatement, env *object.Environment) object.Obj
 		return obj
 	}
 
+	if let.Pattern != nil {
+		if err := bindPattern(let.Pattern, obj, env); err != nil {
+			return err
+		}
+
+		return obj
+	}
+
 	env.Set(let.Name.Value, obj)
 
 	return obj
@@ -138,6 +203,79 @@ func evalFunctionStatement(function *ast.FunctionStatement, env *object.Environm
 	return functionObject
 }
 
+func evalStructStatement(statement *ast.StructStatement, env *object.Environment) object.Object {
+	structObject := &object.Struct{
+		Name:        statement.Name.Value,
+		Fields:      statement.Fields,
+		Defaults:    statement.Defaults,
+		Methods:     map[string]*object.Function{},
+		Environment: env,
+	}
+
+	// The method is the function without self, the self will be bound when it accessed by instance
+	for _, method := range statement.Methods {
+		function := evalFunctionLiteralExpression(method.Function, env).(*object.Function)
+		function.Name = structObject.Name + "." + method.Name.Value
+
+		structObject.Methods[method.Name.Value] = function
+	}
+
+	// Set struct name to environment like function statement, so it can be called to create the instance
+	env.Set(structObject.Name, structObject)
+
+	return structObject
+}
+
+func evalImportStatement(imp *ast.ImportStatement, env *object.Environment) object.Object {
+	// The module is read from file system
+	if err := env.CheckCapability(object.FS_CAPABILITY); err != nil {
+		return err
+	}
+
+	path, err := resolveModulePath(imp.Path, env)
+	if err != nil {
+		return newError("Cannot resolve module %s: %s", imp.Path, err)
+	}
+
+	module := importModule(path, env)
+	if isError(module) == true {
+		return module
+	}
+
+	// Bind the module by alias name first, otherwise use the file name without extension
+	// e.g. import "path/to/lib.sk" as l => l, import "path/to/lib.sk" => lib
+	if imp.Alias != nil {
+		env.Set(imp.Alias.Value, module)
+	} else {
+		env.Set(module.(*object.Module).Name, module)
+	}
+
+	return module
+}
+
+func evalThrowStatement(throw *ast.ThrowStatement, env *object.Environment) object.Object {
+	value := Eval(throw.Value, env)
+	if isError(value) == true {
+		return value
+	}
+
+	var err *object.Error
+
+	switch value := value.(type) {
+	// rethrow the caught error like: catch (e) { throw e }
+	case *object.CaughtError:
+		return value.Error
+	case *object.String:
+		err = object.NewError(object.THROW_ERROR, "%s", value.Value)
+	default:
+		err = object.NewError(object.THROW_ERROR, "%s", value.Inspect())
+	}
+
+	err.Value = value
+
+	return err
+}
+
 func evalReturnStatement(ret *ast.ReturnStatement, env *object.Environment) object.Object {
 	obj := Eval(ret.ReturnValue, env)
 
@@ -151,10 +289,10 @@ func evalReturnStatement(ret *ast.ReturnStatement, env *object.Environment) obje
 }
 
 func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) object.Object {
-	var obj object.Object
+	var obj object.Object = NIL
 
 	for _, statement := range block.Statements {
-		obj := Eval(statement, env)
+		obj = Eval(statement, env)
 		if obj != nil {
 			objectType := obj.Type()
 
@@ -188,6 +326,29 @@ func evalStringLiteralExpression(str *ast.StringLiteralExpression, env *object.E
 	}
 }
 
+func evalTemplateLiteralExpression(template *ast.TemplateLiteralExpression, env *object.Environment) object.Object {
+	parts := evalExpressions(template.Parts, env)
+
+	if len(parts) == 1 && isError(parts[0]) == true {
+		return parts[0]
+	}
+
+	return evalTemplateOperatorExpression(parts)
+}
+
+// Concatenate the evaluated parts of template string, the value which is not string will be inspected
+func evalTemplateOperatorExpression(parts []object.Object) object.Object {
+	var out strings.Builder
+
+	for _, part := range parts {
+		out.WriteString(part.Inspect())
+	}
+
+	return &object.String{
+		Value: out.String(),
+	}
+}
+
 func evalNilLiteralExpression(n *ast.NilLiteralExpression, env *object.Environment) object.Object {
 	return NIL
 }
@@ -197,7 +358,7 @@ func evalIdentifierExpression(identifer *ast.IdentifierExpression, env *object.E
 		return value
 	}
 
-	if builtIn, ok := builtins.BuiltIns[identifer.Value]; ok {
+	if builtIn, ok := FindBuiltIn(identifer.Value, env); ok {
 		return builtIn
 	}
 
@@ -205,9 +366,12 @@ func evalIdentifierExpression(identifer *ast.IdentifierExpression, env *object.E
 }
 
 func evalAssignExpression(assign *ast.AssignExpression, env *object.Environment) object.Object {
-	left := Eval(assign.Left, env)
-	if isError(left) == true {
-		return left
+	// The variable must be defined before assign,
+	// the target of index and dot will be read once when assign, e.g. a.b.c = 1 only reads a.b
+	if _, ok := assign.Left.(*ast.IdentifierExpression); ok {
+		if left := Eval(assign.Left, env); isError(left) == true {
+			return left
+		}
 	}
 
 	value := Eval(assign.Value, env)
@@ -215,24 +379,21 @@ func evalAssignExpression(assign *ast.AssignExpression, env *object.Environment)
 		return value
 	}
 
+	switch left := assign.Left.(type) {
 	// Identifier
-	if identifierExpression, ok := assign.Left.(*ast.IdentifierExpression); ok {
-		env.Set(identifierExpression.Value, value)
+	case *ast.IdentifierExpression:
+		env.Assign(left.Value, value)
 
 		return NIL
-	}
-
 	// Index
-	if indexExpression, ok := assign.Left.(*ast.IndexExpression); ok {
-		return evalAssignIndexExpression(indexExpression, value, env)
-	}
-
+	case *ast.IndexExpression:
+		return evalAssignIndexExpression(left, value, env)
 	// Dot
-	if dotExpression, ok := assign.Left.(*ast.DotExpression); ok {
-		return evalAssignDotExpression(dotExpression, value, env)
+	case *ast.DotExpression:
+		return evalAssignDotExpression(left, value, env)
+	default:
+		return newError("Expected identifier or index expression but got %s", assign.Left.String())
 	}
-
-	return newError("Expected identifier or index expression but got %s: %s", left.Type(), left.Inspect())
 }
 
 func evalAssignIndexExpression(indexExpression *ast.IndexExpression, value object.Object, env *object.Environment) object.Object {
@@ -241,16 +402,26 @@ func evalAssignIndexExpression(indexExpression *ast.IndexExpression, value objec
 		return obj
 	}
 
+	indexObject := Eval(indexExpression.Index, env)
+	if isError(indexObject) == true {
+		return indexObject
+	}
+
+	return evalAssignIndexOperatorExpression(obj, indexObject, value)
+}
+
+func evalAssignIndexOperatorExpression(obj object.Object, indexObject object.Object, value object.Object) object.Object {
 	// Is array?
 	if arrayObject, ok := obj.(*object.Array); ok {
-		indexObject := Eval(indexExpression.Index, env)
+		if indexIntegerObject, ok := indexObject.(*object.Integer); ok {
+			indexValue := indexIntegerObject.Value
+			maxLength := int64(len(arrayObject.Elements) - 1)
 
-		if isError(indexObject) == true {
-			return indexObject
-		}
+			if indexValue < 0 || indexValue > maxLength {
+				return newError("Array index out of range, Got: %d, Expected: 0 to %d", indexValue, maxLength)
+			}
 
-		if indexIntegerObject, ok := indexObject.(*object.Integer); ok {
-			arrayObject.Elements[indexIntegerObject.Value] = value
+			arrayObject.Elements[indexValue] = value
 		} else {
 			return newError("Cannot assign array index with %s", indexObject.Inspect())
 		}
@@ -258,21 +429,20 @@ func evalAssignIndexExpression(indexExpression *ast.IndexExpression, value objec
 
 	// Is hash?
 	if hashObject, ok := obj.(*object.Hash); ok {
-		keyObject := Eval(indexExpression.Index, env)
-
-		if isError(keyObject) == true {
-			return keyObject
-		}
-
-		if hashKey, ok := keyObject.(object.Hashable); ok {
+		if hashKey, ok := indexObject.(object.Hashable); ok {
 			hashed := hashKey.HashKey()
 
+			// Keep the insertion order for the new key
+			if _, exists := hashObject.Pairs[hashed]; exists == false {
+				hashObject.Order = append(hashObject.Order, hashed)
+			}
+
 			hashObject.Pairs[hashed] = object.HashPair{
-				Key:   keyObject,
+				Key:   indexObject,
 				Value: value,
 			}
 		} else {
-			return newError("Cannot assign hash index with %s", keyObject.Inspect())
+			return newError("Cannot assign hash index with %s", indexObject.Inspect())
 		}
 	}
 
@@ -285,17 +455,41 @@ func evalAssignDotExpression(dotExpression *ast.DotExpression, value object.Obje
 		return obj
 	}
 
-	// Is hash?
-	if hashObject, ok := obj.(*object.Hash); ok {
-		keyObject := Eval(dotExpression.Item, env)
+	keyObject := Eval(dotExpression.Item, env)
+	if isError(keyObject) == true {
+		return keyObject
+	}
+
+	return evalAssignDotOperatorExpression(obj, keyObject, value)
+}
+
+func evalAssignDotOperatorExpression(obj object.Object, keyObject object.Object, value object.Object) object.Object {
+	// Is instance? only the declared field can be assigned
+	if instance, ok := obj.(*object.Instance); ok {
+		name, ok := keyObject.(*object.String)
+		if ok == false {
+			return newError("Cannot use %s as field name", keyObject.Type())
+		}
 
-		if isError(keyObject) == true {
-			return keyObject
+		if _, exists := instance.Fields[name.Value]; exists == false {
+			return newError("%s has no field %s", instance.Struct.Name, name.Value)
 		}
 
+		instance.Fields[name.Value] = value
+
+		return NIL
+	}
+
+	// Is hash?
+	if hashObject, ok := obj.(*object.Hash); ok {
 		if hashKey, ok := keyObject.(object.Hashable); ok {
 			hashed := hashKey.HashKey()
 
+			// Keep the insertion order for the new key
+			if _, exists := hashObject.Pairs[hashed]; exists == false {
+				hashObject.Order = append(hashObject.Order, hashed)
+			}
+
 			hashObject.Pairs[hashed] = object.HashPair{
 				Key:   keyObject,
 				Value: value,
@@ -303,9 +497,12 @@ func evalAssignDotExpression(dotExpression *ast.DotExpression, value object.Obje
 		} else {
 			return newError("Cannot assign hash index with %s", keyObject.Inspect())
 		}
+
+		return NIL
 	}
 
-	return NIL
+	// Only the key of hash can be assigned, e.g. a.b.c = 1 when a.b is not hash
+	return newError("Cannot assign %s on %s", keyObject.Inspect(), obj.Type())
 }
 
 func evalArrayLiteralExpression(array *ast.ArrayLiteralExpression, env *object.Environment) object.Object {
@@ -315,12 +512,20 @@ func evalArrayLiteralExpression(array *ast.ArrayLiteralExpression, env *object.E
 		return elements[0]
 	}
 
+	if err := env.Allocate(int64(len(elements))); err != nil {
+		return err
+	}
+
 	return &object.Array{
 		Elements: elements,
 	}
 }
 
 func evalHashLiteralExpression(hash *ast.HashLiteralExpression, env *object.Environment) object.Object {
+	if err := env.Allocate(int64(len(hash.Order))); err != nil {
+		return err
+	}
+
 	hashObject := &object.Hash{
 		Order: []object.HashKey{},
 		Pairs: make(map[object.HashKey]object.HashPair),
@@ -370,6 +575,8 @@ func evalHashLiteralExpression(hash *ast.HashLiteralExpression, env *object.Envi
 func evalFunctionLiteralExpression(function *ast.FunctionLiteralExpression, env *object.Environment) object.Object {
 	return &object.Function{
 		Parameters:  function.Parameters,
+		Defaults:    function.Defaults,
+		Rest:        function.Rest,
 		Block:       function.Block,
 		Environment: env,
 	}
@@ -386,16 +593,20 @@ func evalRangeExpression(rng *ast.RangeExpression, env *object.Environment) obje
 		return end
 	}
 
+	return evalRangeOperatorExpression(start, end, env)
+}
+
+func evalRangeOperatorExpression(start object.Object, end object.Object, env *object.Environment) object.Object {
 	switch {
 	// int..int
 	case start.Type() == object.INTEGER_OBJECT && end.Type() == object.INTEGER_OBJECT:
-		return evalRangeIntegerExpression(start, end)
+		return evalRangeIntegerExpression(start, end, env)
 	// float..float
 	case start.Type() == object.FLOAT_OBJECT && end.Type() == object.FLOAT_OBJECT:
-		return evalRangeFloatExpression(start, end)
+		return evalRangeFloatExpression(start, end, env)
 	// string..string
 	case start.Type() == object.STRING_OBJECT && end.Type() == object.STRING_OBJECT:
-		return evalRangeStringExpression(start, end)
+		return evalRangeStringExpression(start, end, env)
 	default:
 		return newError(
 			"Range operator not support for %s (%s) to %s (%s)",
@@ -413,18 +624,33 @@ func evalCallExpression(call *ast.CallExpression, env *object.Environment) objec
 		return function
 	}
 
-	// Evaluate call argument1, argument2
-	arguments := evalExpressions(call.Arguments, env)
-	if len(arguments) == 1 && isError(arguments[0]) == true {
-		return arguments[0]
+	// Evaluate call argument1, argument2 and the named arguments like name: value
+	arguments, named, err := evalCallArguments(call.Arguments, env)
+	if err != nil {
+		return err
 	}
 
-	// Apply to call arguments to function
-	result := applyFunction(env, function, arguments)
-	if isError(result) == true {
-		return newError("Error calling %s: %s", call.Function, result.Inspect())
+	// Apply to call arguments to function in a new frame of call stack
+	callStack := env.CallStack()
+	if callStack.Depth() >= env.MaxCallDepth() {
+		return env.CallDepthError()
 	}
 
+	callStack.Push(object.StackFrame{
+		Function: call.Function.String(),
+		Position: call.StartPos(),
+	})
+
+	result := applyFunction(env, function, arguments, named)
+
+	// Locate the error by call expression, because built-in function and arguments checking will not locate it,
+	// and keep the call stack when the error raised, the rethrown error will keep its original call stack
+	if err, ok := locateError(result, call).(*object.Error); ok && err.Stack == nil {
+		err.Stack = callStack.Frames()
+	}
+
+	callStack.Pop()
+
 	return result
 }
 
@@ -439,6 +665,10 @@ func evalIndexExpression(index *ast.IndexExpression, env *object.Environment) ob
 		return idx
 	}
 
+	return evalIndexOperatorExpression(left, idx)
+}
+
+func evalIndexOperatorExpression(left object.Object, idx object.Object) object.Object {
 	switch {
 	// array[integer]
 	case left.Type() == object.ARRAY_OBJECT && idx.Type() == object.INTEGER_OBJECT:
@@ -465,10 +695,26 @@ func evalDotExpression(dot *ast.DotExpression, env *object.Environment) object.O
 		return idx
 	}
 
+	return evalDotOperatorExpression(left, idx, env)
+}
+
+func evalDotOperatorExpression(left object.Object, idx object.Object, env *object.Environment) object.Object {
 	switch {
-	// hash.hashable
+	// hash.hashable or hash.method
 	case left.Type() == object.HASH_OBJECT:
-		return evalHashIndexExpression(left, idx)
+		return evalHashDotExpression(left, idx, env)
+	// module.member
+	case left.Type() == object.MODULE_OBJECT:
+		return evalModuleDotExpression(left, idx)
+	// error.member
+	case left.Type() == object.CAUGHT_ERROR_OBJECT:
+		return evalCaughtErrorDotExpression(left, idx)
+	// instance.field or instance.method
+	case left.Type() == object.INSTANCE_OBJECT:
+		return evalInstanceDotExpression(left, idx)
+	// object.method
+	case idx.Type() == object.STRING_OBJECT:
+		return evalMethodDotExpression(left, idx, env)
 	default:
 		return newError("Index operator not support for %s on %s", idx.Inspect(), left.Type())
 	}
@@ -480,7 +726,11 @@ func evalPrefixExpression(prefix *ast.PrefixExpression, env *object.Environment)
 		return right
 	}
 
-	switch prefix.Operator {
+	return evalPrefixOperatorExpression(prefix.Operator, right)
+}
+
+func evalPrefixOperatorExpression(operator string, right object.Object) object.Object {
+	switch operator {
 	case "!":
 		return evalBangOperatorExpression(right)
 	case "-":
@@ -488,18 +738,27 @@ func evalPrefixExpression(prefix *ast.PrefixExpression, env *object.Environment)
 	case "+":
 		return evalPlusPrefixOperatorExpression(right)
 	default:
-		return newError("Unknown operator %s with %s", prefix.Operator, right.Type())
+		return newError("Unknown operator %s with %s", operator, right.Type())
+	}
+}
+
+// The right side of && and || is evaluated only when the left side cannot decide the result,
+// the result is the deciding operand instead of boolean, e.g. name || "default"
+func evalLogicalExpression(logical *ast.InfixExpression, env *object.Environment) object.Object {
+	left := Eval(logical.Left, env)
+	if isError(left) == true {
+		return left
+	}
+
+	if isTruthy(left) == (logical.Operator == "||") {
+		return left
 	}
+
+	return Eval(logical.Right, env)
 }
 
 func evalInfixExpression(left object.Object, operator string, right object.Object, env *object.Environment) object.Object {
 	switch {
-	// and
-	case operator == "&&":
-		return nativeBoolToBooleanObject(objectToNativeBoolean(left) && objectToNativeBoolean(right))
-	// or
-	case operator == "||":
-		return nativeBoolToBooleanObject(objectToNativeBoolean(left) || objectToNativeBoolean(right))
 	// int operator int
 	case left.Type() == object.INTEGER_OBJECT && right.Type() == object.INTEGER_OBJECT:
 		return evalIntegerIntegerInfixExpression(left, operator, right)
@@ -515,12 +774,18 @@ func evalInfixExpression(left object.Object, operator string, right object.Objec
 	// string operator string
 	case left.Type() == object.STRING_OBJECT && right.Type() == object.STRING_OBJECT:
 		return evalStringStringInfixExpression(left, operator, right)
+	// boolean operator boolean, compare by value because the boolean may be created by built-in function
+	case left.Type() == object.BOOLEAN_OBJECT && right.Type() == object.BOOLEAN_OBJECT:
+		return evalBooleanBooleanInfixExpression(left, operator, right)
+	// nil operator nil
+	case left.Type() == object.NIL_OBJECT && right.Type() == object.NIL_OBJECT:
+		return evalNilNilInfixExpression(left, operator, right)
 	// array operator array
 	case left.Type() == object.ARRAY_OBJECT && right.Type() == object.ARRAY_OBJECT:
 		return evalArrayArrayInfixExpression(left, operator, right, env)
 	// hash operator hash
 	case left.Type() == object.HASH_OBJECT && right.Type() == object.HASH_OBJECT:
-		return evalHashHashInfixExpression(left, operator, right)
+		return evalHashHashInfixExpression(left, operator, right, env)
 	// compare other object types like left and right data type are different
 	case operator == "==":
 		return nativeBoolToBooleanObject(left == right)
@@ -552,6 +817,80 @@ func evalIfExpression(ifExp *ast.IfExpression, env *object.Environment) object.O
 	return NIL
 }
 
+func evalMatchExpression(match *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(match.Subject, env)
+	if isError(subject) == true {
+		return subject
+	}
+
+	for _, arm := range match.Arms {
+		bindings := map[string]object.Object{}
+
+		matched, err := matchPattern(arm.Pattern, subject, bindings, env)
+		if err != nil {
+			return err
+		}
+
+		if matched == false {
+			continue
+		}
+
+		// The bound names are set into the scope of arm, so the guard and block can use them
+		// without replacing the variables of the same name
+		armEnvironment := object.NewBlockEnvironment(env)
+
+		for name, value := range bindings {
+			armEnvironment.Set(name, value)
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnvironment)
+			if isError(guard) == true {
+				return guard
+			}
+
+			if isTruthy(guard) == false {
+				continue
+			}
+		}
+
+		return Eval(arm.Block, armEnvironment)
+	}
+
+	return newError("No match arm for %s", subject.Inspect())
+}
+
+func evalTryExpression(try *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(try.Block, env)
+
+	// Catch the error by catch block if the catch block defined, e.g. try { ... } catch (e) { ... },
+	// but the exit function should always stop the program
+	if err, ok := result.(*object.Error); ok && err.Kind != object.EXIT_ERROR && try.CatchBlock != nil {
+		// The error is bound in the scope of catch block, so it does not replace the variable of the same name
+		catchEnvironment := object.NewBlockEnvironment(env)
+
+		if try.Catch != nil {
+			catchEnvironment.Set(try.Catch.Value, &object.CaughtError{
+				Error: err,
+			})
+		}
+
+		result = Eval(try.CatchBlock, catchEnvironment)
+	}
+
+	// Always run the finally block, but the result will be replaced when it break the flow by error, return and so on
+	if try.Finally != nil {
+		finally := Eval(try.Finally, env)
+
+		switch finally.(type) {
+		case *object.Error, *object.ReturnValue, *object.Break, *object.Continue:
+			return finally
+		}
+	}
+
+	return result
+}
+
 func evalForEverExpression(forever *ast.ForEverExpression, env *object.Environment) object.Object {
 	for {
 		block := Eval(forever.Block, env)
@@ -582,6 +921,9 @@ func evalForEverExpression(forever *ast.ForEverExpression, env *object.Environme
 
 func evalForEachArrayOrRangeExpression(arrayOrRange *ast.ForEachArrayOrRangeExpression, env *object.Environment) object.Object {
 	iterable := Eval(arrayOrRange.Iterable, env)
+	if isError(iterable) == true {
+		return iterable
+	}
 
 	_, ok := iterable.(object.Iterable)
 	if ok == false {
@@ -594,13 +936,23 @@ func evalForEachArrayOrRangeExpression(arrayOrRange *ast.ForEachArrayOrRangeExpr
 	case *object.Array:
 		items = iter.Elements
 		break
+	case *object.String:
+		items = iter.Chars()
+		break
 	default:
 		return newError("%s is a %s, not support for loop", iter.Inspect(), iter.Type())
 	}
 
 	for key, value := range items {
 		env.Set("_loopKey", &object.Integer{Value: int64(key)})
-		env.Set(arrayOrRange.Value, value)
+
+		if arrayOrRange.Pattern != nil {
+			if err := bindPattern(arrayOrRange.Pattern, value, env); err != nil {
+				return err
+			}
+		} else {
+			env.Set(arrayOrRange.Value, value)
+		}
 
 		block := Eval(arrayOrRange.Block, env)
 
@@ -630,6 +982,9 @@ func evalForEachArrayOrRangeExpression(arrayOrRange *ast.ForEachArrayOrRangeExpr
 
 func evalForEachHashExpression(hash *ast.ForEachHashExpression, env *object.Environment) object.Object {
 	iterable := Eval(hash.Iterable, env)
+	if isError(iterable) == true {
+		return iterable
+	}
 
 	_, ok := iterable.(object.Iterable)
 	if ok == false {
@@ -683,11 +1038,20 @@ func nativeBoolToBooleanObject(value bool) object.Object {
 }
 
 // For call expression
-func applyFunction(env *object.Environment, function object.Object, arguments []object.Object) object.Object {
+func applyFunction(env *object.Environment, function object.Object, arguments []object.Object, named map[string]object.Object) object.Object {
+	// Only the custom function and struct have the parameter names
+	switch function.(type) {
+	case *object.Function, *object.Struct:
+	default:
+		if len(named) > 0 {
+			return newError("named arguments are not supported by %s", function.Type())
+		}
+	}
+
 	switch fn := function.(type) {
 	// custom function
 	case *object.Function:
-		extendEnvironment, err := extendFunctionEnvironment(fn, arguments)
+		extendEnvironment, err := extendFunctionEnvironment(fn, arguments, named)
 		if err != nil {
 			return err
 		}
@@ -695,47 +1059,432 @@ func applyFunction(env *object.Environment, function object.Object, arguments []
 		evaluated := Eval(fn.Block, extendEnvironment)
 
 		return unwrapReturnValue(evaluated)
+	// struct, the fields are bound like the parameters of function
+	case *object.Struct:
+		if err := env.Allocate(int64(len(fn.Fields))); err != nil {
+			return err
+		}
+
+		extendEnvironment, err := extendFunctionEnvironment(fn.Constructor(), arguments, named)
+		if err != nil {
+			return err
+		}
+
+		instance := &object.Instance{
+			Struct: fn,
+			Fields: make(map[string]object.Object, len(fn.Fields)),
+		}
+
+		for _, field := range fn.Fields {
+			instance.Fields[field.Value], _ = extendEnvironment.Get(field.Value)
+		}
+
+		return instance
 	// built-in function
 	case *object.BuiltIn:
+		if err := env.CheckPermission(fn); err != nil {
+			return err
+		}
+
 		return fn.Function(env, arguments...)
+	// method of built-in type, e.g. "abc".upper()
+	case *object.BoundMethod:
+		if err := env.CheckPermission(fn.Method); err != nil {
+			return err
+		}
+
+		return fn.Method.Function(env, append([]object.Object{fn.Receiver}, arguments...)...)
 	default:
 		return newError("%s is not a function", fn.Type())
 	}
 }
 
-func extendFunctionEnvironment(function *object.Function, arguments []object.Object) (*object.Environment, *object.Error) {
+func extendFunctionEnvironment(function *object.Function, arguments []object.Object, named map[string]object.Object) (*object.Environment, *object.Error) {
 	// Create scoped environment for current function
 	environment := object.NewEnclosedEnvironment(function.Environment)
 
-	if len(arguments) != len(function.Parameters) {
+	if len(arguments) > len(function.Parameters) && function.Rest == nil {
+		expected := strconv.Itoa(len(function.Parameters))
+		if len(function.Defaults) > 0 {
+			expected = "at most " + expected
+		}
+
 		return nil, newError(
-			"not enough arguments for %s function, Got: %s, Expected: %s",
-			function.Inspect(), arguments, function.Parameters,
+			"too many arguments for %s, Got: %d, Expected: %s",
+			function.Signature(), len(arguments), expected,
 		)
 	}
 
-	// Setup variable by parameter is the name, arguments is the value
+	// The named argument must be one of parameters and not passed by position already
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		index := -1
+		for parameterIndex, parameter := range function.Parameters {
+			if parameter.Value == name {
+				index = parameterIndex
+			}
+		}
+
+		if index < 0 {
+			return nil, newError("unknown argument %s for %s", name, function.Signature())
+		}
+
+		if index < len(arguments) {
+			return nil, newError("argument %s for %s is given more than once", name, function.Signature())
+		}
+	}
+
+	// Setup variable by parameter is the name, arguments is the value,
+	// the default value is evaluated in function scope, so it can refer to the previous parameters
 	for index, parameter := range function.Parameters {
-		environment.Set(parameter.Value, arguments[index])
+		if index < len(arguments) {
+			environment.Set(parameter.Value, arguments[index])
+
+			continue
+		}
+
+		if value, ok := named[parameter.Value]; ok {
+			environment.Set(parameter.Value, value)
+
+			continue
+		}
+
+		defaultValue, ok := function.Defaults[parameter.Value]
+		if ok == false {
+			return nil, newError("missing argument %s for %s", parameter.Value, function.Signature())
+		}
+
+		value := Eval(defaultValue, environment)
+		if err, ok := value.(*object.Error); ok {
+			return nil, err
+		}
+
+		environment.Set(parameter.Value, value)
+	}
+
+	// The remaining arguments are collected into array
+	if function.Rest != nil {
+		rest := []object.Object{}
+		if len(arguments) > len(function.Parameters) {
+			rest = append(rest, arguments[len(function.Parameters):]...)
+		}
+
+		environment.Set(function.Rest.Value, &object.Array{Elements: rest})
 	}
 
 	return environment, nil
 }
 
+// Bind the value to the names of destructuring pattern like [a, b, ...rest] or {name, age: years, ...rest},
+// the missing element or key will be nil
+func bindPattern(pattern ast.Expression, value object.Object, env *object.Environment) *object.Error {
+	switch pattern := pattern.(type) {
+	case *ast.IdentifierExpression:
+		// The name "_" is used to skip the value like let [_, b] = array
+		if pattern.Value != "_" {
+			env.Set(pattern.Value, value)
+		}
+	case *ast.ArrayPattern:
+		array, ok := value.(*object.Array)
+		if ok == false {
+			return newError("Cannot destructure %s (%s) by array pattern", value.Inspect(), value.Type())
+		}
+
+		for index, element := range pattern.Elements {
+			var item object.Object = NIL
+			if index < len(array.Elements) {
+				item = array.Elements[index]
+			}
+
+			if err := bindPattern(element, item, env); err != nil {
+				return err
+			}
+		}
+
+		if pattern.Rest != nil {
+			rest := []object.Object{}
+			if len(array.Elements) > len(pattern.Elements) {
+				rest = append(rest, array.Elements[len(pattern.Elements):]...)
+			}
+
+			bindPattern(pattern.Rest, &object.Array{Elements: rest}, env)
+		}
+	case *ast.HashPattern:
+		hash, ok := value.(*object.Hash)
+		if ok == false {
+			return newError("Cannot destructure %s (%s) by hash pattern", value.Inspect(), value.Type())
+		}
+
+		taken := map[object.HashKey]bool{}
+
+		for _, pair := range pattern.Pairs {
+			key := (&object.String{Value: pair.Key.Value}).HashKey()
+
+			var item object.Object = NIL
+			if hashPair, ok := hash.Pairs[key]; ok {
+				item = hashPair.Value
+			}
+
+			taken[key] = true
+
+			if err := bindPattern(pair.Value, item, env); err != nil {
+				return err
+			}
+		}
+
+		if pattern.Rest != nil {
+			bindPattern(pattern.Rest, restHash(hash, taken), env)
+		}
+	default:
+		return newError("Cannot destructure by %s", pattern.String())
+	}
+
+	return nil
+}
+
+// Report the value is matched by the pattern of match arm, the bound names are collected into bindings
+func matchPattern(pattern ast.Expression, value object.Object, bindings map[string]object.Object, env *object.Environment) (bool, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.IdentifierExpression:
+		if pattern.Value != "_" {
+			bindings[pattern.Value] = value
+		}
+
+		return true, nil
+	case *ast.OrPattern:
+		// Collect the bindings of each alternative separately, so the unmatched one will not be kept
+		for _, alternative := range pattern.Patterns {
+			alternativeBindings := map[string]object.Object{}
+
+			matched, err := matchPattern(alternative, value, alternativeBindings, env)
+			if err != nil {
+				return false, err
+			}
+
+			if matched == true {
+				for name, bound := range alternativeBindings {
+					bindings[name] = bound
+				}
+
+				return true, nil
+			}
+		}
+
+		return false, nil
+	case *ast.ArrayPattern:
+		array, ok := value.(*object.Array)
+		if ok == false {
+			return false, nil
+		}
+
+		// The length must be the same, or at least the number of elements when the rest is defined
+		if len(array.Elements) < len(pattern.Elements) || (pattern.Rest == nil && len(array.Elements) != len(pattern.Elements)) {
+			return false, nil
+		}
+
+		for index, element := range pattern.Elements {
+			matched, err := matchPattern(element, array.Elements[index], bindings, env)
+			if err != nil || matched == false {
+				return false, err
+			}
+		}
+
+		if pattern.Rest != nil {
+			rest := append([]object.Object{}, array.Elements[len(pattern.Elements):]...)
+
+			matchPattern(pattern.Rest, &object.Array{Elements: rest}, bindings, env)
+		}
+
+		return true, nil
+	case *ast.HashPattern:
+		hash, ok := value.(*object.Hash)
+		if ok == false {
+			return false, nil
+		}
+
+		taken := map[object.HashKey]bool{}
+
+		// The key must be existed in hash
+		for _, pair := range pattern.Pairs {
+			key := (&object.String{Value: pair.Key.Value}).HashKey()
+
+			hashPair, ok := hash.Pairs[key]
+			if ok == false {
+				return false, nil
+			}
+
+			taken[key] = true
+
+			matched, err := matchPattern(pair.Value, hashPair.Value, bindings, env)
+			if err != nil || matched == false {
+				return false, err
+			}
+		}
+
+		if pattern.Rest != nil {
+			matchPattern(pattern.Rest, restHash(hash, taken), bindings, env)
+		}
+
+		return true, nil
+	case *ast.RangeExpression:
+		// The range is end-exclusive like the range expression, e.g. 1..10 matches 1 to 9
+		start := Eval(pattern.Start, env)
+		if isError(start) == true {
+			return false, start.(*object.Error)
+		}
+
+		end := Eval(pattern.End, env)
+		if isError(end) == true {
+			return false, end.(*object.Error)
+		}
+
+		return compareObjects(value, ">=", start, env) == true && compareObjects(value, "<", end, env) == true, nil
+	default:
+		literal := Eval(pattern, env)
+		if isError(literal) == true {
+			return false, literal.(*object.Error)
+		}
+
+		// Compare the same type only, e.g. 1 does not match "1", but 1 matches 1.0
+		return compareObjects(value, "==", literal, env), nil
+	}
+}
+
+// Compare the objects by infix operator, the unsupported comparison like 1 < "a" is false instead of error
+func compareObjects(left object.Object, operator string, right object.Object, env *object.Environment) bool {
+	result := evalInfixExpression(left, operator, right, env)
+	if isError(result) == true {
+		return false
+	}
+
+	return isTruthy(result)
+}
+
+// Returns the hash which contains the pairs of hash except the taken keys
+func restHash(hash *object.Hash, taken map[object.HashKey]bool) *object.Hash {
+	rest := &object.Hash{
+		Order: []object.HashKey{},
+		Pairs: make(map[object.HashKey]object.HashPair),
+	}
+
+	for _, hashKey := range hash.Order {
+		if taken[hashKey] == false {
+			rest.Order = append(rest.Order, hashKey)
+			rest.Pairs[hashKey] = hash.Pairs[hashKey]
+		}
+	}
+
+	return rest
+}
+
 func unwrapReturnValue(obj object.Object) object.Object {
 	// Return value only if current object is return value object
 	if returnValue, ok := obj.(*object.ReturnValue); ok {
-		return returnValue
+		return returnValue.Value
 	}
 
 	return obj
 }
 
+// For import statement
+func resolveModulePath(path string, env *object.Environment) (string, error) {
+	// Default extension for module path like: import lib
+	if filepath.Ext(path) == "" {
+		path = path + ".sk"
+	}
+
+	// Relative path should be resolved from the directory of importing file,
+	// otherwise (e.g. eval or cli mode) resolve it from current working directory
+	if filepath.IsAbs(path) == false {
+		baseDirectory := "."
+
+		if file := env.File(); file != "" {
+			baseDirectory = filepath.Dir(file)
+		}
+
+		path = filepath.Join(baseDirectory, path)
+	}
+
+	return filepath.Abs(path)
+}
+
+func importModule(path string, env *object.Environment) object.Object {
+	modules := env.Modules()
+
+	// Each module will be evaluated once only
+	if module, ok := modules.Loaded[path]; ok {
+		return module
+	}
+
+	// Module is imported again before it finished loading, e.g. a.sk -> b.sk -> a.sk
+	if modules.IsLoading(path) == true {
+		chain := append(append([]string{}, modules.Loading...), path)
+
+		return newError("Import cycle detected: %s", strings.Join(chain, " -> "))
+	}
+
+	contentBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return newError("Cannot open module %s: %s", path, err)
+	}
+
+	theLexer := lexer.NewLexer(string(contentBytes))
+	theLexer.SetFile(path)
+
+	theParser := parser.NewParser(theLexer)
+	theProgram := theParser.Parse()
+
+	if len(theParser.Errors()) > 0 {
+		return newError("Cannot parse module %s: %s", path, strings.Join(theParser.Errors(), ", "))
+	}
+
+	modules.Loading = append(modules.Loading, path)
+	defer func() {
+		modules.Loading = modules.Loading[:len(modules.Loading)-1]
+	}()
+
+	moduleEnvironment := object.NewModuleEnvironment(env, path)
+
+	evaluated := Eval(theProgram, moduleEnvironment)
+	if isError(evaluated) == true {
+		return evaluated
+	}
+
+	module := &object.Module{
+		Name:        strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Path:        path,
+		Environment: moduleEnvironment,
+	}
+
+	modules.Loaded[path] = module
+
+	return module
+}
+
 // For range expression
-func evalRangeIntegerExpression(start object.Object, end object.Object) object.Object {
+func evalRangeIntegerExpression(start object.Object, end object.Object, env *object.Environment) object.Object {
 	startObject := start.(*object.Integer)
 	endObject := end.(*object.Integer)
 
+	// Count the allocations before creating the elements, the large range may run out of memory,
+	// the difference is counted in unsigned integer which will not overflow like -9223372036854775808..1
+	if endObject.Value > startObject.Value {
+		count := uint64(endObject.Value) - uint64(startObject.Value)
+		if count > math.MaxInt64 {
+			count = math.MaxInt64
+		}
+
+		if err := env.Allocate(int64(count)); err != nil {
+			return err
+		}
+	}
+
 	elements := make([]object.Object, 0)
 	for i := startObject.Value; i < endObject.Value; i++ {
 		elements = append(elements, &object.Integer{
@@ -748,10 +1497,22 @@ func evalRangeIntegerExpression(start object.Object, end object.Object) object.O
 	}
 }
 
-func evalRangeFloatExpression(start object.Object, end object.Object) object.Object {
+func evalRangeFloatExpression(start object.Object, end object.Object, env *object.Environment) object.Object {
 	startObject := start.(*object.Float)
 	endObject := end.(*object.Float)
 
+	// The count of huge range like 0.0..1e300 cannot be converted to integer
+	if endObject.Value > startObject.Value {
+		count := int64(math.MaxInt64)
+		if size := math.Ceil((endObject.Value - startObject.Value) / 0.1); size < math.MaxInt64 {
+			count = int64(size)
+		}
+
+		if err := env.Allocate(count); err != nil {
+			return err
+		}
+	}
+
 	elements := make([]object.Object, 0)
 	for i := startObject.Value; i < endObject.Value; i += 0.1 {
 		elements = append(elements, &object.Float{
@@ -764,15 +1525,15 @@ func evalRangeFloatExpression(start object.Object, end object.Object) object.Obj
 	}
 }
 
-func evalRangeStringExpression(start object.Object, end object.Object) object.Object {
+func evalRangeStringExpression(start object.Object, end object.Object, env *object.Environment) object.Object {
 	startObject := start.(*object.String)
 	endObject := end.(*object.String)
 
-	if len(startObject.Value) > 1 {
+	if utf8.RuneCountInString(startObject.Value) != 1 {
 		return newError("Range start value must be char only")
 	}
 
-	if len(endObject.Value) > 1 {
+	if utf8.RuneCountInString(endObject.Value) != 1 {
 		return newError("Range end value must be char only")
 	}
 
@@ -781,6 +1542,15 @@ func evalRangeStringExpression(start object.Object, end object.Object) object.Ob
 	startByte := int32([]rune(startObject.Value)[0])
 	endByte := int32([]rune(endObject.Value)[0])
 
+	count := endByte - startByte
+	if count < 0 {
+		count = -count
+	}
+
+	if err := env.Allocate(int64(count)); err != nil {
+		return err
+	}
+
 	if startByte >= endByte {
 		// E.g. z -> a
 		for i := startByte; i > endByte; i-- {
@@ -841,17 +1611,129 @@ func evalStringIndexExpression(left object.Object, index object.Object) object.O
 	stringObject := left.(*object.String)
 	indexObject := index.(*object.Integer)
 
-	stringValue := stringObject.Value
+	// The index is counted by characters instead of bytes, e.g. "é"[0] is "é"
+	chars := []rune(stringObject.Value)
 	indexValue := indexObject.Value
 
-	maxLength := int64(len(stringValue) - 1)
+	maxLength := int64(len(chars) - 1)
 
 	if indexValue < 0 || indexValue > maxLength {
 		return NIL
 	}
 
 	return &object.String{
-		Value: string(stringObject.Value[indexValue]),
+		Value: string(chars[indexValue]),
+	}
+}
+
+// For dot expression
+func evalHashDotExpression(left object.Object, item object.Object, env *object.Environment) object.Object {
+	hashObject := left.(*object.Hash)
+
+	key, ok := item.(object.Hashable)
+	if ok == false {
+		return newError("Cannot use %s as hash key", item.Type())
+	}
+
+	// The key of hash is preferred, so the method can be called only when the hash has no same key
+	if pair, ok := hashObject.Pairs[key.HashKey()]; ok {
+		return pair.Value
+	}
+
+	if name, ok := item.(*object.String); ok {
+		if method, ok := FindMethod(left.Type(), name.Value, env); ok {
+			return &object.BoundMethod{Name: name.Value, Receiver: left, Method: method}
+		}
+	}
+
+	return NIL
+}
+
+func evalMethodDotExpression(left object.Object, item object.Object, env *object.Environment) object.Object {
+	name := item.(*object.String).Value
+
+	method, ok := FindMethod(left.Type(), name, env)
+	if ok == false {
+		return newError("no method %s on %s", name, left.Type())
+	}
+
+	return &object.BoundMethod{Name: name, Receiver: left, Method: method}
+}
+
+func evalModuleDotExpression(left object.Object, item object.Object) object.Object {
+	moduleObject := left.(*object.Module)
+
+	name, ok := item.(*object.String)
+	if ok == false {
+		return newError("Cannot use %s as module member name", item.Type())
+	}
+
+	member, ok := moduleObject.Environment.Get(name.Value)
+	if ok == false {
+		return newError("Module %s has no member %s", moduleObject.Name, name.Value)
+	}
+
+	return member
+}
+
+func evalInstanceDotExpression(left object.Object, item object.Object) object.Object {
+	instance := left.(*object.Instance)
+
+	name, ok := item.(*object.String)
+	if ok == false {
+		return newError("Cannot use %s as field name", item.Type())
+	}
+
+	if value, ok := instance.Fields[name.Value]; ok {
+		return value
+	}
+
+	method, ok := instance.Struct.Methods[name.Value]
+	if ok == false {
+		return newError("%s has no field or method %s", instance.Struct.Name, name.Value)
+	}
+
+	// Bind the instance to self in the scope of method
+	environment := object.NewEnclosedEnvironment(method.Environment)
+	environment.Set("self", instance)
+
+	return &object.Function{
+		Name:        method.Name,
+		Parameters:  method.Parameters,
+		Defaults:    method.Defaults,
+		Rest:        method.Rest,
+		Block:       method.Block,
+		Environment: environment,
+	}
+}
+
+func evalCaughtErrorDotExpression(left object.Object, item object.Object) object.Object {
+	err := left.(*object.CaughtError).Error
+
+	name, ok := item.(*object.String)
+	if ok == false {
+		return newError("Cannot use %s as error member name", item.Type())
+	}
+
+	switch name.Value {
+	case "message":
+		return &object.String{Value: err.Message}
+	case "kind":
+		return &object.String{Value: err.Kind}
+	case "file":
+		return &object.String{Value: err.Position.File}
+	case "line":
+		return &object.Integer{Value: int64(err.Position.Line)}
+	case "column":
+		return &object.Integer{Value: int64(err.Position.Column)}
+	case "value":
+		if err.Value == nil {
+			return NIL
+		}
+
+		return err.Value
+	default:
+		return newError("Error has no member %s", name.Value)
 	}
 }
 
@@ -932,6 +1814,10 @@ func evalIntegerIntegerInfixExpression(left object.Object, operator string, righ
 	case "*":
 		return &object.Integer{Value: leftValue * rightValue}
 	case "/":
+		if rightValue == 0 {
+			return newError("Division by zero: %d / %d", leftValue, rightValue)
+		}
+
 		return &object.Integer{Value: leftValue / rightValue}
 	case "<":
 		return nativeBoolToBooleanObject(leftValue < rightValue)
@@ -1076,6 +1962,31 @@ func evalStringStringInfixExpression(left object.Object, operator string, right
 	}
 }
 
+func evalBooleanBooleanInfixExpression(left object.Object, operator string, right object.Object) object.Object {
+	leftValue := left.(*object.Boolean).Value
+	rightValue := right.(*object.Boolean).Value
+
+	switch operator {
+	case "==":
+		return nativeBoolToBooleanObject(leftValue == rightValue)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue != rightValue)
+	default:
+		return newError("Unknown operator %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalNilNilInfixExpression(left object.Object, operator string, right object.Object) object.Object {
+	switch operator {
+	case "==":
+		return TRUE
+	case "!=":
+		return FALSE
+	default:
+		return newError("Unknown operator %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
 func evalArrayArrayInfixExpression(left object.Object, operator string, right object.Object, env *object.Environment) object.Object {
 	leftArray := left.(*object.Array)
 	rightArray := right.(*object.Array)
@@ -1085,6 +1996,10 @@ func evalArrayArrayInfixExpression(left object.Object, operator string, right ob
 
 	switch operator {
 	case "+":
+		if err := env.Allocate(int64(len(leftElements) + len(rightElements))); err != nil {
+			return err
+		}
+
 		return &object.Array{Elements: append(leftElements, rightElements...)}
 	case "==":
 		if len(leftElements) != len(rightElements) {
@@ -1094,7 +2009,7 @@ func evalArrayArrayInfixExpression(left object.Object, operator string, right ob
 		for i := range leftElements {
 			compareResult := evalInfixExpression(leftElements[i], "==", rightElements[i], env)
 
-			if objectToNativeBoolean(compareResult) != true {
+			if isTruthy(compareResult) != true {
 				return FALSE
 			}
 		}
@@ -1111,7 +2026,7 @@ func evalArrayArrayInfixExpression(left object.Object, operator string, right ob
 	}
 }
 
-func evalHashHashInfixExpression(left object.Object, operator string, right object.Object) object.Object {
+func evalHashHashInfixExpression(left object.Object, operator string, right object.Object, env *object.Environment) object.Object {
 	leftHash := left.(*object.Hash)
 	rightHash := right.(*object.Hash)
 
@@ -1120,6 +2035,10 @@ func evalHashHashInfixExpression(left object.Object, operator string, right obje
 
 	switch operator {
 	case "+":
+		if err := env.Allocate(int64(len(rightHash.Order))); err != nil {
+			return err
+		}
+
 		for _, hashKey := range rightHash.Order {
 			pair, _ := rightPairs[hashKey]
 
@@ -1167,7 +2086,7 @@ func evalHashHashInfixExpression(left object.Object, operator string, right obje
 
 		return FALSE
 	case "!=":
-		if evalHashHashInfixExpression(left, "==", right) == TRUE {
+		if evalHashHashInfixExpression(left, "==", right, env) == TRUE {
 			return FALSE
 		}
 
@@ -1178,56 +2097,53 @@ func evalHashHashInfixExpression(left object.Object, operator string, right obje
 }
 
 // Helper functions
-func evalExpressions(expressions []ast.Expression, env *object.Environment) []object.Object {
-	var objects []object.Object
+// Evaluate the positional and named arguments of call expression, the named arguments are placed after positional arguments by parser
+func evalCallArguments(expressions []ast.Expression, env *object.Environment) ([]object.Object, map[string]object.Object, object.Object) {
+	var (
+		arguments []object.Object
+		named     map[string]object.Object
+	)
 
 	for _, expression := range expressions {
+		if namedArgument, ok := expression.(*ast.NamedArgumentExpression); ok {
+			evaluated := Eval(namedArgument.Value, env)
+			if isError(evaluated) == true {
+				return nil, nil, evaluated
+			}
+
+			if named == nil {
+				named = map[string]object.Object{}
+			}
+
+			named[namedArgument.Name.Value] = evaluated
+
+			continue
+		}
+
 		evaluated := Eval(expression, env)
 		if isError(evaluated) == true {
-			return []object.Object{evaluated}
+			return nil, nil, evaluated
 		}
 
-		objects = append(objects, evaluated)
+		arguments = append(arguments, evaluated)
 	}
 
-	return objects
+	return arguments, named, nil
 }
 
-func objectToNativeBoolean(obj object.Object) bool {
-	if ret, ok := obj.(*object.ReturnValue); ok {
-		obj = ret.Value
-	}
+func evalExpressions(expressions []ast.Expression, env *object.Environment) []object.Object {
+	var objects []object.Object
 
-	switch o := obj.(type) {
-	case *object.Boolean:
-		return o.Value
-	case *object.Nil:
-		return false
-	case *object.String:
-		return len(o.Value) != 0
-	case *object.Integer:
-		if o.Value == 0 {
-			return false
-		}
-		return true
-	case *object.Float:
-		if o.Value == 0.0 {
-			return false
-		}
-		return true
-	case *object.Array:
-		if len(o.Elements) == 0 {
-			return false
-		}
-		return true
-	case *object.Hash:
-		if len(o.Pairs) == 0 {
-			return false
+	for _, expression := range expressions {
+		evaluated := Eval(expression, env)
+		if isError(evaluated) == true {
+			return []object.Object{evaluated}
 		}
-		return true
-	default:
-		return true
+
+		objects = append(objects, evaluated)
 	}
+
+	return objects
 }
 
 func isTruthy(obj object.Object) bool {
@@ -1276,8 +2192,15 @@ func isError(obj object.Object) bool {
 	return false
 }
 
-func newError(format string, values ...interface{}) *object.Error {
-	return &object.Error{
-		Message: fmt.Sprintf(format, values...),
+// Set the start position of node to error if it is not located
+func locateError(obj object.Object, node ast.Node) object.Object {
+	if err, ok := obj.(*object.Error); ok && err.Position.IsValid() == false && node != nil {
+		err.Position = node.StartPos()
 	}
+
+	return obj
+}
+
+func newError(format string, values ...interface{}) *object.Error {
+	return object.NewError(object.RUNTIME_ERROR, format, values...)
 }
@@ -1,11 +1,15 @@
-package evaluator
+package evaluator_test
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 
@@ -86,6 +90,11 @@ func TestStringLiteralExpression(t *testing.T) {
 		}{
 			{`"foo"`, "foo"},
 			{`"foobar"`, "foobar"},
+			{`"a\tb\n"`, "a\tb\n"},
+			{`"say \"hi\" \\o/"`, `say "hi" \o/`},
+			{`"caf\u00e9 \u{1F600}"`, "café 😀"},
+			{"`raw \\n ${name}`", `raw \n ${name}`},
+			{"\"a\nb\" + `\nc`", "a\nb\nc"},
 		}
 
 		for index, expected := range expecteds {
@@ -100,6 +109,35 @@ func TestStringLiteralExpression(t *testing.T) {
 	})
 }
 
+func TestTemplateLiteralExpression(t *testing.T) {
+	Convey("Template literal expression test", t, func() {
+		expecteds := []struct {
+			source string
+			result interface{}
+		}{
+			{`let name = "foo"; "hello ${name}!"`, "hello foo!"},
+			{`let user = {"name": "foo"}; "${user.name}"`, "foo"},
+			{`"${1 + 2} ${1.5} ${true} ${nil} ${[1, "a"]}"`, "3 1.5 true nil [1, a]"},
+			{`"${ {"a": 1}.a }"`, "1"},
+			{`let a = "b"; "${"${a}${a}"}c"`, "bbc"},
+			{`"\${a}"`, "${a}"},
+			{`"${upper("a")}" + "${len([1, 2])}"`, "A2"},
+			{`let f = func(x) { "x=${x}" }; f(1)`, "x=1"},
+			{`len("${1}${2}")`, 2},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				testLiteralObject(testEval(expected.source), expected.result)
+			})
+		}
+
+		Convey("Error test", func() {
+			testErrorObject(testEval(`"a${b}"`), "Identifier not found: b")
+		})
+	})
+}
+
 func TestNilLiteralExpression(t *testing.T) {
 	Convey("Nil literal expression test", t, func() {
 		expected := struct {
@@ -124,6 +162,7 @@ func TestIdentifierExpression(t *testing.T) {
 			}{
 				{"foo", "Identifier not found: foo"},
 				{"foobar", "Identifier not found: foobar"},
+				{"名前", "Identifier not found: 名前"},
 			}
 
 			for index, expected := range expecteds {
@@ -164,16 +203,19 @@ func TestIdentifierExpression(t *testing.T) {
 		})
 
 		Convey("Register and Get built-in function test", func() {
-			RegisterBuiltIn(
+			environment := object.NewEnvironment()
+			environment.SetBuiltIn(
 				"fooFunction",
-				func(environment *object.Environment, arguments ...object.Object) object.Object {
-					return &object.String{
-						Value: "foo function",
-					}
+				&object.BuiltIn{
+					Function: func(environment *object.Environment, arguments ...object.Object) object.Object {
+						return &object.String{
+							Value: "foo function",
+						}
+					},
 				},
 			)
 
-			testBuiltInObject(testEval("fooFunction"), "foo function")
+			testBuiltInObject(testEvalWithEnv("fooFunction", environment), "foo function")
 		})
 	})
 }
@@ -218,6 +260,15 @@ func TestAssignExpression(t *testing.T) {
 
 			{`let a = {}; a.foo = 12; a.bar = "baz"; a.foo;`, 12},
 			{`let a = {}; a.foo = 12; a.bar = "baz"; a.bar;`, "baz"},
+
+			{`let name = "bar"; let a = {"name": 1}; a.name`, 1},
+			{`let name = "bar"; let a = {}; a.name = 2; a["name"]`, 2},
+			{`let name = "bar"; let a = {}; a[name] = 3; a.bar`, 3},
+			{`let a = {"b": {"c": 1}}; a.b.c = 2; a.b.c`, 2},
+			{`let a = {"b": {}}; a.b.c = 3; a["b"]["c"]`, 3},
+			{`let a = {}; a.b = {}; a.b.c = {}; a.b.c.d = 4; a.b.c.d`, 4},
+			{`let a = {"b": [{}]}; a.b[0].c = 5; a.b[0].c`, 5},
+			{`let calls = []; let a = {}; let get = func() { push(calls, 1); a }; get().b = 1; len(calls)`, 1},
 		}
 
 		for index, expected := range expecteds {
@@ -229,6 +280,26 @@ func TestAssignExpression(t *testing.T) {
 				})
 			})
 		}
+
+		Convey("Error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`b = 1`, "Identifier not found: b"},
+				{`let a = {}; a.b.c = 1`, "Cannot assign c on NIL_OBJECT"},
+				{`let a = "foo"; a.b = 1`, "Cannot assign b on STRING_OBJECT"},
+				{`let a = {}; a.b = c`, "Identifier not found: c"},
+				{`let a = [1]; a[5] = 1`, "Array index out of range, Got: 5, Expected: 0 to 0"},
+				{`let a = [1]; a[-1] = 1`, "Array index out of range, Got: -1, Expected: 0 to 0"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testErrorObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
 	})
 }
 
@@ -307,6 +378,7 @@ func TestRangeExpression(t *testing.T) {
 			{`"a".."c"`, 2, []string{"a", "b"}},
 			{`"f".."a"`, 5, []string{"f", "e", "d", "c", "b"}},
 			{`"z".."v"`, 4, []string{"z", "y", "x", "w"}},
+			{`"α".."δ"`, 3, []string{"α", "β", "γ"}},
 		}
 
 		for index, expected := range expecteds {
@@ -369,6 +441,59 @@ func TestCallExpression(t *testing.T) {
 	})
 }
 
+func TestCallExpressionWithParameters(t *testing.T) {
+	Convey("Call expression with parameters test", t, func() {
+		Convey("Value test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`func f(a, b = 2) { [a, b] }; f(1)`, "[1, 2]"},
+				{`func f(a, b = 2) { [a, b] }; f(1, 3)`, "[1, 3]"},
+				{`func f(a, b = a * 2) { [a, b] }; f(4)`, "[4, 8]"},
+				{`let x = 1; func f(a = x) { a }; x = 5; f()`, "5"},
+				{`func f(a, ...rest) { [a, rest] }; f(1)`, "[1, []]"},
+				{`func f(a, ...rest) { [a, rest] }; f(1, 2, 3)`, "[1, [2, 3]]"},
+				{`func f(a, b = 2, ...rest) { [a, b, rest] }; f(1, 3, 4)`, "[1, 3, [4]]"},
+				{`func f(a, b) { [a, b] }; f(b: 3, a: 1)`, "[1, 3]"},
+				{`func f(a, b = 2, c = 3) { [a, b, c] }; f(1, c: 4)`, "[1, 2, 4]"},
+				{`let f = func(a, b = 2) { a + b }; f(a: 1)`, "3"},
+				{`func f(a = missing) { a }; f(1)`, "1"},
+				{`func f(a, b = 2, ...rest) { 1 }; f`, "func(a, b = 2, ...rest) { 1 } "},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					So(testEval(expected.source).Inspect(), ShouldEqual, expected.result)
+				})
+			}
+		})
+
+		Convey("Error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`func f(a, b) { a }; f(1)`, "missing argument b for func(a, b)"},
+				{`func f(a) { a }; f(1, 2)`, "too many arguments for func(a), Got: 2, Expected: 1"},
+				{`func f(a, b = 2) { a }; f(1, 2, 3)`, "too many arguments for func(a, b = 2), Got: 3, Expected: at most 2"},
+				{`func f(a, b = 2) { a }; f(b: 1)`, "missing argument a for func(a, b = 2)"},
+				{`func f(a) { a }; f(1, a: 2)`, "argument a for func(a) is given more than once"},
+				{`func f(a) { a }; f(b: 2)`, "unknown argument b for func(a)"},
+				{`func f(a, ...rest) { a }; f(1, rest: 2)`, "unknown argument rest for func(a, ...rest)"},
+				{`func f(a = b) { a }; f()`, "Identifier not found: b"},
+				{`len(value: [])`, "named arguments are not supported by BUILTIN_OBJECT"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testErrorObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+	})
+}
+
 func TestIndexExpression(t *testing.T) {
 	Convey("Index expression test", t, func() {
 		Convey("For array object", func() {
@@ -417,6 +542,11 @@ func TestIndexExpression(t *testing.T) {
 				{`"foobar"[0]`, "f"},
 				{`"foobar"[3]`, "b"},
 				{`"foobar"[5]`, "r"},
+				{`"héllo"[1]`, "é"},
+				{`"héllo"[2]`, "l"},
+				{`"日本語"[2]`, "語"},
+				{`"😀!"[1]`, "!"},
+				{`"é"[1]`, nil},
 			}
 
 			for index, expected := range expecteds {
@@ -536,27 +666,36 @@ func TestInfixExpression(t *testing.T) {
 		Convey("&& operator test", func() {
 			expecteds := []struct {
 				source string
-				result interface{}
+				result string
 			}{
-				{`1 && 2`, true},
-				{`3.3 && 4.4`, true},
-
-				{`"foo" && "bar"`, true},
-
-				{`[] && []`, false},
-				{`[1, 2] && []`, false},
-				{`[1, 2] && [3, 4]`, true},
-
-				{`{} && {}`, false},
-				{`{1: "a"} && {}`, false},
-				{`{1: "a"} && {"b": 2}`, true},
+				{`1 && 2`, "2"},
+				{`0 && 2`, "0"},
+				{`3.3 && 4.4`, "4.4"},
+				{`true && false`, "false"},
+
+				{`"foo" && "bar"`, "bar"},
+				{`"" && "bar"`, ""},
+
+				{`[] && []`, "[]"},
+				{`[1, 2] && []`, "[]"},
+				{`[1, 2] && [3, 4]`, "[3, 4]"},
+
+				{`{} && {}`, "{}"},
+				{`{1: "a"} && {}`, "{}"},
+				{`{1: "a"} && {"b": 2}`, "{b: 2}"},
+
+				{`nil && nil.name`, "nil"},
+				{`let a = nil; a != nil && a.name == "foo"`, "false"},
+				{`let a = {"name": "foo"}; a != nil && a.name == "foo"`, "true"},
+				{`let a = []; false && push(a, 1); len(a)`, "0"},
+				{`let a = []; true && push(a, 1); len(a)`, "1"},
+				{`1 && 2 && 3`, "3"},
+				{`1 && 0 && undefined`, "0"},
 			}
 
 			for index, expected := range expecteds {
 				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
-					evaluated := testEval(expected.source)
-
-					testLiteralObject(evaluated, expected.result)
+					So(testEval(expected.source).Inspect(), ShouldEqual, expected.result)
 				})
 			}
 		})
@@ -564,27 +703,70 @@ func TestInfixExpression(t *testing.T) {
 		Convey("|| operator test", func() {
 			expecteds := []struct {
 				source string
-				result interface{}
+				result string
 			}{
-				{`1 || 2`, true},
-				{`3.3 || 4.4`, true},
-
-				{`"foo" || "bar"`, true},
+				{`1 || 2`, "1"},
+				{`0 || 2`, "2"},
+				{`3.3 || 4.4`, "3.3"},
+				{`false || false`, "false"},
+
+				{`"foo" || "bar"`, "foo"},
+				{`"" || "bar"`, "bar"},
+
+				{`[] || []`, "[]"},
+				{`[1, 2] || []`, "[1, 2]"},
+				{`[1, 2] || [3, 4]`, "[1, 2]"},
+
+				{`{} || {}`, "{}"},
+				{`{1: "a"} || {}`, "{1: a}"},
+				{`{1: "a"} || {"b": 2}`, "{1: a}"},
+
+				{`let name = nil; name || "default"`, "default"},
+				{`let name = "foo"; name || "default"`, "foo"},
+				{`let a = []; true || push(a, 1); len(a)`, "0"},
+				{`let a = []; false || push(a, 1); len(a)`, "1"},
+				{`nil || false || "c"`, "c"},
+				{`0 || 1 || undefined`, "1"},
+				{`nil || 0 && 1`, "0"},
+				{`if (nil || 1) { "yes" } else { "no" }`, "yes"},
+			}
 
-				{`[] || []`, false},
-				{`[1, 2] || []`, true},
-				{`[1, 2] || [3, 4]`, true},
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					So(testEval(expected.source).Inspect(), ShouldEqual, expected.result)
+				})
+			}
+		})
 
-				{`{} || {}`, false},
-				{`{1: "a"} || {}`, true},
-				{`{1: "a"} || {"b": 2}`, true},
+		Convey("&& and || error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`undefined && true`, "Identifier not found: undefined"},
+				{`true && undefined`, "Identifier not found: undefined"},
+				{`false || undefined`, "Identifier not found: undefined"},
 			}
 
 			for index, expected := range expecteds {
 				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
-					evaluated := testEval(expected.source)
+					testErrorObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
 
-					testLiteralObject(evaluated, expected.result)
+		Convey("Division by zero test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`1 / 0`, "Division by zero: 1 / 0"},
+				{`let a = 0; 10 / a`, "Division by zero: 10 / 0"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testErrorObject(testEval(expected.source), expected.result)
 				})
 			}
 		})
@@ -1002,6 +1184,22 @@ func TestForEachArrayOrRangeExpression(t *testing.T) {
 				a;`,
 				10,
 			},
+			{
+				`let a = "";
+				for b in "héllo" {
+					let a = b + a;
+				}
+				a;`,
+				"olléh",
+			},
+			{
+				`let a = 0;
+				for b in "日本語" {
+					let a = a + 1;
+				}
+				a;`,
+				3,
+			},
 			{
 				`let a = 0;
 				for b in [1,2,3,4] {
@@ -1015,6 +1213,14 @@ func TestForEachArrayOrRangeExpression(t *testing.T) {
 				a;`,
 				3,
 			},
+			{
+				`let a = 0;
+				for b in [5,6,7] {
+					let a = a + _loopKey;
+				}
+				a;`,
+				3,
+			},
 			{
 				`let a = 0;
 				for b in [1,2,3,4] {
@@ -1096,6 +1302,224 @@ func TestForEachHashExpression(t *testing.T) {
 	})
 }
 
+func TestMatchExpression(t *testing.T) {
+	Convey("Match expression test", t, func() {
+		Convey("Value test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`match 2 { 1 => "one", 2 => "two", _ => "many" }`, "two"},
+				{`match 5 { 1 => "one", 2 => "two", _ => "many" }`, "many"},
+				{`match 2 { 1 | 2 | 3 => "small", _ => "big" }`, "small"},
+				{`match 1.0 { 1 => "int", _ => "other" }`, "int"},
+				{`match "1" { 1 => "int", "1" => "string" }`, "string"},
+				{`match -1 { -1 => "negative", _ => "other" }`, "negative"},
+				{`match nil { nil => "nil", _ => "other" }`, "nil"},
+				{`match true { false => "no", true => "yes" }`, "yes"},
+				{`match 5 { 0..5 => "low", 5..10 => "high" }`, "high"},
+				{`match 4.5 { 0..5 => "low", 5..10 => "high" }`, "low"},
+				{`match [1, 2, 3] { [] => "empty", [first, ...] => first }`, "1"},
+				{`match [1, 2, 3] { [a, b] => "two", [a, ...rest] => rest }`, "[2, 3]"},
+				{`match [] { [] => "empty", _ => "other" }`, "empty"},
+				{`match [1, [2, 3]] { [1, [x, 3]] => x }`, "2"},
+				{`match {"type": "x", "data": 1} { {type: "y"} => "y", {type: "x", data} => data }`, "1"},
+				{`match {"a": 1, "b": 2} { {a, ...others} => others }`, "{b: 2}"},
+				{`match {"a": 1} { {b} => "b", _ => "missing" }`, "missing"},
+				{`match 11 { n if n > 10 => "big ${n}", n => "small ${n}" }`, "big 11"},
+				{`match 3 { n if n > 10 => "big ${n}", n => "small ${n}" }`, "small 3"},
+				{`match [1, 2] { [a, b] | [a, b, _] => a + b }`, "3"},
+				{`match 1 { 1 => { let a = 2; a * 3 } 2 => 0 }`, "6"},
+				{`match 1 { 1 => ({"a": 1}) }`, "{a: 1}"},
+				{`func f(x) { match x { 0 => { return "zero"; }, _ => "other" }; "after" }; [f(0), f(1)]`, "[zero, after]"},
+				{`let x = match [3, 4] { [a, b] => a * b }; x`, "12"},
+				{`let n = 5; match 3 { n if n > 10 => 1, _ => 0 }; n`, "5"},
+				{`let x = 1; match [7] { [x] => x }; x`, "1"},
+				{`let x = 1; [match [7] { [x] => x }, x]`, "[7, 1]"},
+				{`let total = 0; match [1, 2] { [a, b] => { total = a + b } }; total`, "3"},
+				{`match [1] { [a] => { let b = a } }; try { [a, b] } catch (e) { e.message }`, "Identifier not found: a"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					So(testEval(expected.source).Inspect(), ShouldEqual, expected.result)
+				})
+			}
+		})
+
+		Convey("Error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`match 3 { 1 => "one", 2 => "two" }`, "No match arm for 3"},
+				{`match [1] { [a, b] => a }`, "No match arm for [1]"},
+				{`match 1 { n if n.foo => n }`, "no method foo on INTEGER_OBJECT"},
+				{`match missing { _ => 1 }`, "Identifier not found: missing"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testErrorObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+	})
+}
+
+func TestStructStatement(t *testing.T) {
+	Convey("Struct statement test", t, func() {
+		Convey("Value test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`struct Point { x, y }`, "struct Point { x, y }"},
+				{`struct Point { x, y = 0 }; Point`, "struct Point { x, y = 0 }"},
+				{`struct Point { x, y }; Point(1, 2)`, "Point{x: 1, y: 2}"},
+				{`struct Point { x, y }; Point(y: 2, x: 1)`, "Point{x: 1, y: 2}"},
+				{`struct Point { x, y = x * 2 }; Point(3)`, "Point{x: 3, y: 6}"},
+				{`struct Point { x, y }; let p = Point(1, 2); p.x + p.y`, "3"},
+				{`struct Point { x, y }; let p = Point(1, 2); p.x = 10; p`, "Point{x: 10, y: 2}"},
+				{`struct Point { x, y; func sum() { self.x + self.y } }; Point(1, 2).sum()`, "3"},
+				{`struct Point { x, y, func scale(n = 2) { Point(self.x * n, self.y * n) } }; Point(1, 2).scale()`, "Point{x: 2, y: 4}"},
+				{`struct Counter { count = 0, func add(n) { self.count = self.count + n; self } }; let c = Counter(); c.add(2).add(3); c.count`, "5"},
+				{`struct Point { x, y, func sum() { self.x + self.y } }; let sum = Point(1, 2).sum; sum()`, "3"},
+				{`struct Point { x, y }; [1, 2].map(func(n) { Point(n, n) })`, "[Point{x: 1, y: 1}, Point{x: 2, y: 2}]"},
+				{`struct Point { x }; map([1, 2], Point)`, "[Point{x: 1}, Point{x: 2}]"},
+				{`struct Node { value, next = nil }; let list = Node(1, Node(2)); list.next.value`, "2"},
+				{`struct Point { x, y }; type(Point(1, 2))`, "INSTANCE_OBJECT"},
+				{`struct Empty {}; [Empty, Empty()]`, "[struct Empty {}, Empty{}]"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					So(testEval(expected.source).Inspect(), ShouldEqual, expected.result)
+				})
+			}
+		})
+
+		Convey("Error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`struct Point { x, y }; Point(1)`, "missing argument y for Point(x, y)"},
+				{`struct Point { x, y = 0 }; Point(1, 2, 3)`, "too many arguments for Point(x, y = 0), Got: 3, Expected: at most 2"},
+				{`struct Point { x, y }; Point(1, z: 2)`, "unknown argument z for Point(x, y)"},
+				{`struct Point { x, y }; Point(1, 2).z`, "Point has no field or method z"},
+				{`struct Point { x, y }; let p = Point(1, 2); p.z = 3`, "Point has no field z"},
+				{`struct Point { x, func move(dx) { self.x + dx } }; Point(1).move()`, "missing argument dx for Point.move(dx)"},
+				{`struct Point { x, func bad() { self.z } }; Point(1).bad()`, "Point has no field or method z"},
+				{`struct Point { x, func bad() { self } }; self`, "Identifier not found: self"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testErrorObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+	})
+}
+
+func TestTryExpression(t *testing.T) {
+	Convey("Try expression test", t, func() {
+		Convey("Catch error test", func() {
+			expecteds := []struct {
+				source string
+				result interface{}
+			}{
+				{`try { 1 } catch (e) { 2 }`, 1},
+				{`try { throw "foo" } catch (e) { 2 }`, 2},
+				{`try { throw "foo" } catch (e) { e.message }`, "foo"},
+				{`try { throw "foo" } catch (e) { e.kind }`, "ThrowError"},
+				{"\n\ntry { throw 123 } catch (e) { e.line }", 3},
+				{`try { throw 123 } catch (e) { e.value }`, 123},
+				{"try {\n  a + 1 } catch (e) { e.column }", 3},
+				{`try { a } catch (e) { e.file }`, ""},
+				{`try { throw 123 } catch (e) { e.message }`, "123"},
+				{`try { 1 + "a" } catch (e) { e.message }`, "Type mismatch INTEGER_OBJECT + STRING_OBJECT"},
+				{`try { 1 + "a" } catch (e) { e.kind }`, "RuntimeError"},
+				{`try { a } catch { "caught" }`, "caught"},
+				{`func a() { throw "foo" }; try { a() } catch (e) { e.message }`, "foo"},
+				{`func a(b) { b }; try { a() } catch (e) { e.kind }`, "RuntimeError"},
+				{`try { testFail("bar") } catch (e) { e.message }`, "failed by bar"},
+				{`try { try { throw "foo" } catch (e) { throw e } } catch (e) { e.message }`, "foo"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					evaluated := testEvalWithEnv(expected.source, testFailEnvironment())
+
+					testLiteralObject(evaluated, expected.result)
+				})
+			}
+		})
+
+		Convey("Catch scope test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`let e = 1; try { throw "boom" } catch (e) { e.message }; e`, "1"},
+				{`let e = 1; try { throw "boom" } catch (e) { e = 2 }; e`, "1"},
+				{`let a = 1; try { throw "boom" } catch (e) { a = e.message }; a`, "boom"},
+				{`try { throw "boom" } catch (e) { let b = 1 }; try { b } catch (e) { e.message }`, "Identifier not found: b"},
+				{`func f() { try { throw "boom" } catch (e) { e } }; let e = f(); e.message`, "boom"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					So(testEval(expected.source).Inspect(), ShouldEqual, expected.result)
+				})
+			}
+		})
+
+		Convey("Finally block test", func() {
+			expecteds := []struct {
+				source string
+				result interface{}
+			}{
+				{`let a = 1; try { a = 2 } finally { a = a + 1 }; a`, 3},
+				{`let a = 1; try { throw "foo" } catch (e) { a = 2 } finally { a = a + 1 }; a`, 3},
+				{`try { 1 } finally { 2 }`, 1},
+				{`func a() { try { return 1 } finally { 2 } }; a()`, 1},
+				{`func a() { try { return 1 } finally { return 2 } }; a()`, 2},
+				{`let a = 0; for v in [1, 2, 3] { try { break } finally { a = v } }; a`, 1},
+				{`let a = 0; try { try { throw "foo" } finally { a = 1 } } catch (e) { a = a + 1 }; a`, 2},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					evaluated := testEval(expected.source)
+
+					testLiteralObject(evaluated, expected.result)
+				})
+			}
+		})
+
+		Convey("Uncaught error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`try { throw "foo" } finally { 1 }`, "foo"},
+				{`try { throw "foo" } catch (e) { throw "bar" }`, "bar"},
+				{`try { 1 } finally { throw "bar" }`, "bar"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					evaluated := testEval(expected.source)
+
+					testErrorObject(evaluated, expected.result)
+				})
+			}
+		})
+	})
+}
+
 // Statements
 func TestLetStatement(t *testing.T) {
 	Convey("Let statement test", t, func() {
@@ -1106,6 +1530,8 @@ func TestLetStatement(t *testing.T) {
 			{`let a = 5;`, 5},
 			{`let b = 5.5;`, 5.5},
 			{`let c = "foo";`, "foo"},
+			{`let café = 1; café + 1`, 2},
+			{`let 名前 = "foo"; 名前`, "foo"},
 		}
 
 		for index, expected := range expecteds {
@@ -1118,6 +1544,61 @@ func TestLetStatement(t *testing.T) {
 	})
 }
 
+func TestDestructuring(t *testing.T) {
+	Convey("Destructuring test", t, func() {
+		Convey("Value test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`let [a, b] = [1, 2]; [b, a]`, "[2, 1]"},
+				{`let [a, b, c] = [1, 2]; c`, "nil"},
+				{`let [a, ...rest] = [1, 2, 3]; rest`, "[2, 3]"},
+				{`let [a, b, ...rest] = [1]; [b, rest]`, "[nil, []]"},
+				{`let [] = [1]; 1`, "1"},
+				{`let [a, [b, c]] = [1, [2, 3]]; a + b + c`, "6"},
+				{`let {name, age: years} = {"name": "foo", "age": 8}; [name, years]`, "[foo, 8]"},
+				{`let {name, missing} = {"name": "foo"}; missing`, "nil"},
+				{`let {a, ...others} = {"a": 1, "b": 2, "c": 3}; others`, "{b: 2, c: 3}"},
+				{`let {user: {name}, tags: [first]} = {"user": {"name": "foo"}, "tags": ["x", "y"]}; [name, first]`, "[foo, x]"},
+				{`let [a, b] = [1, 2]`, "[1, 2]"},
+				{`let _ = 0; let [_, b, ...] = [1, 2, 3]; [_, b]`, "[0, 2]"},
+				{`func f() { return 1, "a"; }; let [n, s] = f(); [n, s]`, "[1, a]"},
+				{`func f() { return 1, 2 + 3; }; f()`, "[1, 5]"},
+				{`let total = 0; for [k, v] in [["a", 1], ["b", 2]] { total = total + v; }; total`, "3"},
+				{`let names = []; for [{name}] in [[{"name": "a"}], [{"name": "b"}]] { push(names, name); }; names`, "[a, b]"},
+				{`let names = []; for {name, age: years} in [{"name": "a", "age": 1}, {"name": "b", "age": 2}] { push(names, name + "${years}"); }; names`, "[a1, b2]"},
+				{`let a = 0; for { a = a + 1; if (a > 2) { break; } }; a`, "3"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					So(testEval(expected.source).Inspect(), ShouldEqual, expected.result)
+				})
+			}
+		})
+
+		Convey("Error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`let [a] = 1;`, "Cannot destructure 1 (INTEGER_OBJECT) by array pattern"},
+				{`let {a} = [1];`, "Cannot destructure [1] (ARRAY_OBJECT) by hash pattern"},
+				{`let [{a}] = [[1]];`, "Cannot destructure [1] (ARRAY_OBJECT) by hash pattern"},
+				{`for [a, b] in [1, 2] { a }`, "Cannot destructure 1 (INTEGER_OBJECT) by array pattern"},
+				{`for {a} in [1, 2] { a }`, "Cannot destructure 1 (INTEGER_OBJECT) by hash pattern"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testErrorObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+	})
+}
+
 func TestLetStatementWithFunctionLiteralExpression(t *testing.T) {
 	Convey("Let statement with function literal expression", t, func() {
 		source := "let a = func(a, b) { c };"
@@ -1171,18 +1652,940 @@ func TestFunctionStatement(t *testing.T) {
 	})
 }
 
-func TestBlockStatement(t *testing.T) {
-	Convey("Block statement test", t, func() {
-		expecteds := []struct {
-			source      string
-			returnValue interface{}
-		}{
-			{"let a = 1;", 1},
-			{"let a = 1; let b = 2;", 2},
-
-			{"let a = 1.1;", 1.1},
-			{"let a = 1.1; let b = 2.2;", 2.2},
-
+func TestClosure(t *testing.T) {
+	Convey("Closure test", t, func() {
+		Convey("Variable resolving test", func() {
+			expecteds := []struct {
+				source string
+				result interface{}
+			}{
+				{`func g() { let x = 1; let f = func() { x }; x = 2; f() }; g()`, 2},
+				{`func g(x) { let f = func() { x }; let x = x + 1; f() }; g(1)`, 2},
+				{`func g() { let fs = []; for i in 1..4 { fs.push(func() { i }) }; "${fs.map(func(f) { f() })}" }; g()`, "[3, 3, 3]"},
+				{`func g() { let f = func() { y }; let y = 5; f() }; g()`, 5},
+				{`func g() { func run() { helper() }; func helper() { 7 }; run() }; g()`, 7},
+				{`func g(x) { let f = func() { func() { x } }; f()() }; g(3)`, 3},
+				{`let t = 1; func g(n) { if (n > 0) { let t = n }; t }; g(0)`, 1},
+				{`func g() { let a = 0; for b in [5, 6, 7] { let a = a + _loopKey }; a }; g()`, 3},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					evaluated := testEval(expected.source)
+
+					testLiteralObject(evaluated, expected.result)
+				})
+			}
+		})
+
+		Convey("Error handling test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`func g(n) { if (n > 0) { let t = n }; t }; g(0)`, "Identifier not found: t"},
+				{`func g(n) { if (n > 0) { let t = n }; func() { t } }; g(0)()`, "Identifier not found: t"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					evaluated := testEval(expected.source)
+
+					testErrorObject(evaluated, expected.result)
+				})
+			}
+		})
+	})
+}
+
+func TestImportStatement(t *testing.T) {
+	Convey("Import statement test", t, func() {
+		Convey("Import module test", func() {
+			expecteds := []struct {
+				source string
+				result interface{}
+			}{
+				{`import "main.sk"; main.total;`, 10},
+				{`import "lib/math.sk"; math.add(1, 2);`, 3},
+				{`import "lib/math" as m; m.add(1, 2);`, 3},
+				{`import greet; greet.prefix;`, "hello "},
+				{`import greet as g; g.say("tom");`, "hello tom"},
+				{`import "lib/math.sk" as a; import "lib/math.sk" as b; a == b;`, true},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					environment := object.NewEnvironment()
+					environment.SetFile("testdata/import/entry.sk")
+
+					evaluated := testEvalWithEnv(expected.source, environment)
+
+					testLiteralObject(evaluated, expected.result)
+				})
+			}
+		})
+
+		Convey("Error handling test", func() {
+			cycleA, _ := filepath.Abs("testdata/import/cycle_a.sk")
+			cycleB, _ := filepath.Abs("testdata/import/cycle_b.sk")
+
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`import cycle_a;`, fmt.Sprintf("Import cycle detected: %s -> %s -> %s", cycleA, cycleB, cycleA)},
+				{`import greet; greet.missing;`, "Module greet has no member missing"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					environment := object.NewEnvironment()
+					environment.SetFile("testdata/import/entry.sk")
+
+					evaluated := testEvalWithEnv(expected.source, environment)
+
+					testErrorObject(evaluated, expected.result)
+				})
+			}
+		})
+
+		Convey("Import requires the fs capability", func() {
+			environment := object.NewEnvironment()
+			environment.SetFile("testdata/import/entry.sk")
+			environment.SetCapabilities([]object.Capability{object.IO_CAPABILITY})
+
+			evaluated := testEvalWithEnv(`import greet; greet.prefix`, environment)
+
+			testErrorObject(evaluated, "Permission denied, the fs capability is not granted")
+			So(evaluated.(*object.Error).Kind, ShouldEqual, object.PERMISSION_ERROR)
+
+			environment.SetCapabilities([]object.Capability{object.FS_CAPABILITY})
+
+			So(testEvalWithEnv(`import greet; greet.prefix`, environment).Inspect(), ShouldEqual, "hello ")
+		})
+	})
+}
+
+func TestThrowStatement(t *testing.T) {
+	Convey("Throw statement test", t, func() {
+		expecteds := []struct {
+			source string
+			result string
+			kind   string
+			line   int
+		}{
+			{`throw "foo"`, "foo", object.THROW_ERROR, 1},
+			{`throw 1 + 2`, "3", object.THROW_ERROR, 1},
+			{"let a = 1;\nthrow [a]", "[1]", object.THROW_ERROR, 2},
+			{`throw a`, "Identifier not found: a", object.RUNTIME_ERROR, 1},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				evaluated := testEval(expected.source)
+
+				testErrorObject(evaluated, expected.result)
+
+				Convey("Error kind and line were matched", func() {
+					So(evaluated.(*object.Error).Kind, ShouldEqual, expected.kind)
+					So(evaluated.(*object.Error).Position.Line, ShouldEqual, expected.line)
+				})
+			})
+		}
+	})
+}
+
+func TestErrorPosition(t *testing.T) {
+	Convey("Error position test", t, func() {
+		expecteds := []struct {
+			source   string
+			position string
+		}{
+			{`a`, "main.sk:1:1"},
+			{"let x = 1;\n  x + \"a\"", "main.sk:2:3"},
+			{"func f(a) {\n  a + b\n}\nf(1)", "main.sk:2:7"},
+			{"func f(a) { a }\nlet y = f()", "main.sk:2:9"},
+			{"let h = {};\nh.b.c", "main.sk:2:1"},
+			{"for x in 5 { }", "main.sk:1:1"},
+			{"1 +\n  foo(1)", "main.sk:2:3"},
+			{"\n  throw \"foo\"", "main.sk:2:3"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %q", index, expected.source), func() {
+				theLexer := lexer.NewLexer(expected.source)
+				theLexer.SetFile("main.sk")
+
+				theParser := parser.NewParser(theLexer)
+				theProgarm := theParser.Parse()
+
+				evaluated := testEngine(theProgarm, object.NewEnvironment())
+
+				err, ok := evaluated.(*object.Error)
+				Convey("Can convert to object (error)", func() {
+					So(ok, ShouldBeTrue)
+				})
+
+				Convey(runMessage("Error position should be equals %s", expected.position), func() {
+					So(err.Position.String(), ShouldEqual, expected.position)
+				})
+			})
+		}
+	})
+}
+
+func TestStackTrace(t *testing.T) {
+	Convey("Stack trace test", t, func() {
+		expecteds := []struct {
+			source     string
+			stackTrace string
+		}{
+			{
+				"1 + a",
+				"RuntimeError: Identifier not found: a\n\nmain()\n\tmain.sk:1:5",
+			},
+			{
+				"func c(x) {\n  x + y\n}\nfunc b(x) { c(x) }\nfunc a() {\n  b(1)\n}\na()",
+				"RuntimeError: Identifier not found: y\n\n" +
+					"c(...)\n\tmain.sk:2:7\n" +
+					"b(...)\n\tmain.sk:4:13\n" +
+					"a(...)\n\tmain.sk:6:3\n" +
+					"main()\n\tmain.sk:8:1",
+			},
+			{
+				"func a() {\n  testFail(1)\n}\na()",
+				"RuntimeError: failed by 1\n\n" +
+					"testFail(...)\n\tmain.sk:2:3\n" +
+					"a(...)\n\tmain.sk:2:3\n" +
+					"main()\n\tmain.sk:4:1",
+			},
+			{
+				"func a(x) { x + \"s\" }\nfunc b() { a(1) }\nb()",
+				"RuntimeError: Type mismatch INTEGER_OBJECT + STRING_OBJECT\n\n" +
+					"a(...)\n\tmain.sk:1:13\n" +
+					"b(...)\n\tmain.sk:2:12\n" +
+					"main()\n\tmain.sk:3:1",
+			},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %q", index, expected.source), func() {
+				theLexer := lexer.NewLexer(expected.source)
+				theLexer.SetFile("main.sk")
+
+				theParser := parser.NewParser(theLexer)
+				theProgarm := theParser.Parse()
+
+				evaluated := testEngine(theProgarm, testFailEnvironment())
+
+				err, ok := evaluated.(*object.Error)
+				Convey("Can convert to object (error)", func() {
+					So(ok, ShouldBeTrue)
+				})
+
+				Convey(runMessage("Stack trace should be equals %q", expected.stackTrace), func() {
+					So(err.StackTrace(), ShouldEqual, expected.stackTrace)
+				})
+			})
+		}
+	})
+}
+
+func TestExitBuiltIn(t *testing.T) {
+	Convey("Exit built-in test", t, func() {
+		Convey("Exit code test", func() {
+			expecteds := []struct {
+				source string
+				code   int
+			}{
+				{`exit()`, 0},
+				{`exit(3)`, 3},
+				{`println(); exit(1); exit(2)`, 1},
+				{`func f(a) { exit(a) }; f(4)`, 4},
+				{`try { exit(5) } catch (e) { 1 }`, 5},
+				{`try { exit(6) } finally { 1 }`, 6},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					evaluated := testEval(expected.source)
+
+					err, ok := evaluated.(*object.Error)
+					Convey("Can convert to object (error)", func() {
+						So(ok, ShouldBeTrue)
+					})
+
+					Convey(runMessage("Exit code should be equals %d", expected.code), func() {
+						code, ok := err.ExitCode()
+
+						So(ok, ShouldBeTrue)
+						So(code, ShouldEqual, expected.code)
+						So(err.Kind, ShouldEqual, object.EXIT_ERROR)
+					})
+				})
+			}
+		})
+
+		Convey("Exit error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`exit("a")`, "exit code must be INTEGER_OBJECT, Got: STRING_OBJECT"},
+				{`exit(1, 2)`, "wrong number of arguments for exit, Got: 2, Expected: 0 or 1"},
+				{`exit(300)`, "exit code out of range, Got: 300, Expected: 0 to 255"},
+				{`exit(-1)`, "exit code out of range, Got: -1, Expected: 0 to 255"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					evaluated := testEval(expected.source)
+
+					testErrorObject(evaluated, expected.result)
+
+					Convey("Error is not exit error", func() {
+						_, ok := evaluated.(*object.Error).ExitCode()
+
+						So(ok, ShouldBeFalse)
+					})
+				})
+			}
+		})
+	})
+}
+
+func TestEnvBuiltIn(t *testing.T) {
+	Convey("Env built-in test", t, func() {
+		os.Setenv("SKRIP_TEST_ENV", "foo")
+		os.Unsetenv("SKRIP_TEST_UNSET_ENV")
+
+		Convey("Env value test", func() {
+			expecteds := []struct {
+				source string
+				result interface{}
+			}{
+				{`env("SKRIP_TEST_ENV")`, "foo"},
+				{`env()["SKRIP_TEST_ENV"]`, "foo"},
+				{`setenv("SKRIP_TEST_ENV", "bar"); env("SKRIP_TEST_ENV")`, "bar"},
+				{`setenv("SKRIP_TEST_SET_ENV", "baz"); env()["SKRIP_TEST_SET_ENV"]`, "baz"},
+				{`env("SKRIP_TEST_UNSET_ENV") == nil`, true},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testLiteralObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+
+		Convey("Env error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`env(1)`, "env name must be STRING_OBJECT, Got: INTEGER_OBJECT"},
+				{`env("a", "b")`, "wrong number of arguments for env, Got: 2, Expected: 0 or 1"},
+				{`setenv("a")`, "wrong number of arguments for setenv, Got: 1, Expected: 2"},
+				{`setenv("a", 1)`, "env value must be STRING_OBJECT, Got: INTEGER_OBJECT"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testErrorObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+	})
+}
+
+func TestCollectionBuiltIns(t *testing.T) {
+	Convey("Collection built-ins test", t, func() {
+		Convey("Value test", func() {
+			expecteds := []struct {
+				source string
+				result interface{}
+			}{
+				{`len("hello")`, 5},
+				{`len([1, 2, 3])`, 3},
+				{`len({"a": 1, "b": 2})`, 2},
+				{`len([])`, 0},
+				{`let a = [1]; push(a, 2, 3); len(a)`, 3},
+				{`let a = [1, 2, 3]; pop(a)`, 3},
+				{`let a = [1, 2, 3]; pop(a); len(a)`, 2},
+				{`pop([]) == nil`, true},
+				{`let a = [1, 2, 3]; shift(a)`, 1},
+				{`let a = [1, 2, 3]; shift(a); a[0]`, 2},
+				{`shift([]) == nil`, true},
+				{`let a = {"a": 1, "b": 2}; delete(a, "a")`, 1},
+				{`let a = {"a": 1, "b": 2}; delete(a, "a"); len(a)`, 1},
+				{`delete({"a": 1}, "b") == nil`, true},
+				{`contains("hello", "ell")`, true},
+				{`contains("hello", "foo")`, false},
+				{`contains([1, "a", [2]], [2])`, true},
+				{`contains([1, 2], 3)`, false},
+				{`contains([1, 2], 2.0)`, true},
+				{`contains({"a": 1}, "a")`, true},
+				{`contains({"a": 1}, "b")`, false},
+				{`contains([1, 2], 2) == true`, true},
+				{`index_of([1, 2, 3], 3)`, 2},
+				{`index_of([1, 2, 3], 4)`, -1},
+				{`index_of([{"a": 1}], {"a": 1})`, 0},
+				{`index_of("hello", "l")`, 2},
+				{`index_of("hello", "z")`, -1},
+				{`reverse("abc")`, "cba"},
+				{`reverse("héllo")`, "olléh"},
+				{`slice("hello", 1, 3)`, "el"},
+				{`slice("héllo", 1, 3)`, "él"},
+				{`slice("日本語", -1)`, "語"},
+				{`len("héllo")`, 5},
+				{`len("😀")`, 1},
+				{`index_of("héllo", "l")`, 2},
+				{`slice("hello", -3)`, "llo"},
+				{`slice("hello", 3, 1)`, ""},
+				{`type(1)`, "INTEGER_OBJECT"},
+				{`type("a")`, "STRING_OBJECT"},
+				{`type([])`, "ARRAY_OBJECT"},
+				{`type({})`, "HASH_OBJECT"},
+				{`type(nil)`, "NIL_OBJECT"},
+				{`type(len)`, "BUILTIN_OBJECT"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testLiteralObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+
+		Convey("Collection test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`push([1], 2, 3)`, "[1, 2, 3]"},
+				{`let a = []; push(a, 1); push(a, [2]); a`, "[1, [2]]"},
+				{`let a = [1, 2, 3]; pop(a); a`, "[1, 2]"},
+				{`let a = [1, 2, 3]; shift(a); a`, "[2, 3]"},
+				{`insert([1, 3], 1, 2)`, "[1, 2, 3]"},
+				{`insert([1, 2], 0, 0)`, "[0, 1, 2]"},
+				{`insert([1, 2], 2, 3)`, "[1, 2, 3]"},
+				{`keys({"b": 1, "a": 2, "c": 3})`, "[b, a, c]"},
+				{`values({"b": 1, "a": 2, "c": 3})`, "[1, 2, 3]"},
+				{`keys({})`, "[]"},
+				{`let a = {"b": 1, "a": 2, "c": 3}; delete(a, "a"); keys(a)`, "[b, c]"},
+				{`let a = {"b": 1, "a": 2}; delete(a, "b"); a["c"] = 3; keys(a)`, "[a, c]"},
+				{`let a = {"b": 1, "a": 2}; delete(a, "b"); a["b"] = 3; keys(a)`, "[a, b]"},
+				{`reverse([1, 2, 3])`, "[3, 2, 1]"},
+				{`let a = [1, 2]; reverse(a); a`, "[1, 2]"},
+				{`slice([1, 2, 3, 4], 1, 3)`, "[2, 3]"},
+				{`slice([1, 2, 3, 4], -2)`, "[3, 4]"},
+				{`slice([1, 2, 3, 4], 0, -1)`, "[1, 2, 3]"},
+				{`slice([1, 2, 3, 4], 10)`, "[]"},
+				{`let a = [1, 2, 3]; let b = slice(a, 0, 2); push(b, 4); a`, "[1, 2, 3]"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					So(testEval(expected.source).Inspect(), ShouldEqual, expected.result)
+				})
+			}
+		})
+
+		Convey("Error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`len(1)`, "argument 1 of len must be STRING_OBJECT, ARRAY_OBJECT or HASH_OBJECT, Got: INTEGER_OBJECT"},
+				{`len()`, "wrong number of arguments for len, Got: 0, Expected: 1"},
+				{`len([], [])`, "wrong number of arguments for len, Got: 2, Expected: 1"},
+				{`push()`, "wrong number of arguments for push, Got: 0, Expected: at least 1"},
+				{`push("a", 1)`, "argument 1 of push must be ARRAY_OBJECT, Got: STRING_OBJECT"},
+				{`pop({})`, "argument 1 of pop must be ARRAY_OBJECT, Got: HASH_OBJECT"},
+				{`shift(nil)`, "argument 1 of shift must be ARRAY_OBJECT, Got: NIL_OBJECT"},
+				{`insert([1], 1)`, "wrong number of arguments for insert, Got: 2, Expected: 3"},
+				{`insert([1], "a", 1)`, "argument 2 of insert must be INTEGER_OBJECT, Got: STRING_OBJECT"},
+				{`insert([1], 2, 1)`, "insert index out of range, Got: 2, Expected: 0 to 1"},
+				{`insert([1], -1, 1)`, "insert index out of range, Got: -1, Expected: 0 to 1"},
+				{`keys([])`, "argument 1 of keys must be HASH_OBJECT, Got: ARRAY_OBJECT"},
+				{`values("a")`, "argument 1 of values must be HASH_OBJECT, Got: STRING_OBJECT"},
+				{`delete([1], 0)`, "argument 1 of delete must be HASH_OBJECT, Got: ARRAY_OBJECT"},
+				{`delete({}, [])`, "Cannot use ARRAY_OBJECT as hash key"},
+				{`contains("a", 1)`, "argument 2 of contains must be STRING_OBJECT, Got: INTEGER_OBJECT"},
+				{`contains(1, 1)`, "argument 1 of contains must be STRING_OBJECT, ARRAY_OBJECT or HASH_OBJECT, Got: INTEGER_OBJECT"},
+				{`index_of({}, 1)`, "argument 1 of index_of must be STRING_OBJECT or ARRAY_OBJECT, Got: HASH_OBJECT"},
+				{`reverse(1)`, "argument 1 of reverse must be STRING_OBJECT or ARRAY_OBJECT, Got: INTEGER_OBJECT"},
+				{`slice([1])`, "wrong number of arguments for slice, Got: 1, Expected: 2 or 3"},
+				{`slice([1], "a")`, "argument 2 of slice must be INTEGER_OBJECT, Got: STRING_OBJECT"},
+				{`slice([1], 0, nil)`, "argument 3 of slice must be INTEGER_OBJECT, Got: NIL_OBJECT"},
+				{`type()`, "wrong number of arguments for type, Got: 0, Expected: 1"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testErrorObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+	})
+}
+
+func TestHigherOrderBuiltIns(t *testing.T) {
+	Convey("Higher-order built-ins test", t, func() {
+		Convey("Value test", func() {
+			expecteds := []struct {
+				source string
+				result interface{}
+			}{
+				{`reduce([1, 2, 3], func(a, b) { a + b })`, 6},
+				{`reduce([1, 2, 3], func(a, b) { a + b }, 10)`, 16},
+				{`reduce([], func(a, b) { a + b }, "empty")`, "empty"},
+				{`reduce(["a", "b"], func(a, b) { b + a })`, "ba"},
+				{`let seen = []; each([1, 2, 3], func(x) { push(seen, x * 2) }); seen[2]`, 6},
+				{`each([1], func(x) { x }) == nil`, true},
+				{`any([1, 2, 3], func(x) { x > 2 })`, true},
+				{`any([1, 2, 3], func(x) { x > 3 })`, false},
+				{`any([], func(x) { true })`, false},
+				{`all([1, 2, 3], func(x) { x > 0 })`, true},
+				{`all([1, 2, 3], func(x) { x > 1 })`, false},
+				{`all([], func(x) { false })`, true},
+				{`find([1, 2, 3], func(x) { x > 1 })`, 2},
+				{`find([1, 2, 3], func(x) { x > 3 }) == nil`, true},
+				{`let k = 2; func f(a) { map(a, func(x) { x * k }) }; f([1, 2])[1]`, 4},
+				{`func f(a, n) { reduce(a, func(s, x) { s + x * n }, 0) }; f([1, 2], 10)`, 30},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testLiteralObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+
+		Convey("Collection test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`map([1, 2, 3], func(x) { x * 2 })`, "[2, 4, 6]"},
+				{`map([], func(x) { x })`, "[]"},
+				{`map([1, "a"], type)`, "[INTEGER_OBJECT, STRING_OBJECT]"},
+				{`map([1], func(x) { })`, "[nil]"},
+				{`let double = func(x) { x * 2 }; map(1..4, double)`, "[2, 4, 6]"},
+				{`map([[1, 2], [3]], func(x) { map(x, func(y) { y + 1 }) })`, "[[2, 3], [4]]"},
+				{`filter(1..7, func(x) { x > 3 })`, "[4, 5, 6]"},
+				{`filter([1, 0, "", "a", nil], func(x) { x })`, "[1, a]"},
+				{`zip([1, 2, 3], ["a", "b"])`, "[[1, a], [2, b]]"},
+				{`zip([1, 2], [3, 4], [5, 6])`, "[[1, 3, 5], [2, 4, 6]]"},
+				{`zip([1], [])`, "[]"},
+				{`flatten([1, [2, [3, [4]]]])`, "[1, 2, [3, [4]]]"},
+				{`flatten([1, [2, [3, [4]]]], 2)`, "[1, 2, 3, [4]]"},
+				{`flatten([1, [2, [3, [4]]]], 0)`, "[1, [2, [3, [4]]]]"},
+				{`keys(group_by([1, 2, 3, 4], func(x) { x > 2 }))`, "[false, true]"},
+				{`values(group_by(["aa", "b", "cc"], len))`, "[[aa, cc], [b]]"},
+				{`sort([3, 1, 2])`, "[1, 2, 3]"},
+				{`sort([2.5, 1, 3])`, "[1, 2.5, 3]"},
+				{`sort(["b", "c", "a"])`, "[a, b, c]"},
+				{`sort([3, 1, 2], func(a, b) { a > b })`, "[3, 2, 1]"},
+				{`sort([3, 1, 2], func(a, b) { b - a })`, "[3, 2, 1]"},
+				{`sort([[2, "b"], [1, "a"], [2, "a"]], func(a, b) { a[0] < b[0] })`, "[[1, a], [2, b], [2, a]]"},
+				{`let a = [2, 1]; sort(a); a`, "[2, 1]"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					So(testEval(expected.source).Inspect(), ShouldEqual, expected.result)
+				})
+			}
+		})
+
+		Convey("Error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`map([1])`, "wrong number of arguments for map, Got: 1, Expected: 2"},
+				{`map(1, func(x) { x })`, "argument 1 of map must be ARRAY_OBJECT, Got: INTEGER_OBJECT"},
+				{`filter([1], 1)`, "argument 2 of filter must be FUNCTION_OBJECT, Got: INTEGER_OBJECT"},
+				{`map([1], func(x) { x + "a" })`, "Type mismatch INTEGER_OBJECT + STRING_OBJECT"},
+				{`map([1], func(x) { throw "foo" })`, "foo"},
+				{`reduce([], func(a, b) { a + b })`, "reduce of empty array with no initial value"},
+				{`reduce([1], func(a, b) { a + b }, 0, 1)`, "wrong number of arguments for reduce, Got: 4, Expected: 2 or 3"},
+				{`group_by([1], func(x) { [x] })`, "Cannot use ARRAY_OBJECT as hash key"},
+				{`sort([1, "a"])`, "Cannot compare STRING_OBJECT and INTEGER_OBJECT"},
+				{`sort([1, 2], func(a, b) { nil })`, "sort comparator must return BOOLEAN_OBJECT or number, Got: NIL_OBJECT"},
+				{`sort([1, 2], func(a, b) { a + "a" })`, "Type mismatch INTEGER_OBJECT + STRING_OBJECT"},
+				{`zip([1], 1)`, "argument 2 of zip must be ARRAY_OBJECT, Got: INTEGER_OBJECT"},
+				{`flatten([1], "a")`, "argument 2 of flatten must be INTEGER_OBJECT, Got: STRING_OBJECT"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testErrorObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+
+		Convey("Stack trace test", func() {
+			source := "func fail(x) {\n  x + y\n}\nmap([1], func(x) { fail(x) })"
+
+			theLexer := lexer.NewLexer(source)
+			theLexer.SetFile("main.sk")
+
+			theParser := parser.NewParser(theLexer)
+			evaluated := testEngine(theParser.Parse(), object.NewEnvironment())
+
+			So(evaluated.(*object.Error).StackTrace(), ShouldEqual, "RuntimeError: Identifier not found: y\n\n"+
+				"fail(...)\n\tmain.sk:2:7\n"+
+				"map(...)\n\tmain.sk:4:20\n"+
+				"main()\n\tmain.sk:4:1")
+		})
+	})
+}
+
+func TestStringBuiltIns(t *testing.T) {
+	Convey("String built-ins test", t, func() {
+		Convey("Value test", func() {
+			expecteds := []struct {
+				source string
+				result interface{}
+			}{
+				{`join(["a", "b", "c"], ", ")`, "a, b, c"},
+				{`join([1, 2.5, true, nil], "-")`, "1-2.5-true-nil"},
+				{`join([], ",")`, ""},
+				{`replace("a.b.c", ".", "/")`, "a/b/c"},
+				{`replace("a.b.c", ".", "/", 1)`, "a/b.c"},
+				{`trim("  foo  ")`, "foo"},
+				{`trim("--foo--", "-")`, "foo"},
+				{`trim_left("  foo  ")`, "foo  "},
+				{`trim_left("xxfoo", "x")`, "foo"},
+				{`trim_right("  foo  ")`, "  foo"},
+				{`trim_right("fooyy", "y")`, "foo"},
+				{`trim_prefix("foobar", "foo")`, "bar"},
+				{`trim_suffix("foobar", "bar")`, "foo"},
+				{`upper("Foo")`, "FOO"},
+				{`lower("Foo")`, "foo"},
+				{`starts_with("foobar", "foo")`, true},
+				{`starts_with("foobar", "bar")`, false},
+				{`ends_with("foobar", "bar")`, true},
+				{`ends_with("foobar", "foo")`, false},
+				{`index_of("foobar", "bar")`, 3},
+				{`repeat("ab", 3)`, "ababab"},
+				{`repeat("ab", 0)`, ""},
+				{`pad_left("7", 3, "0")`, "007"},
+				{`pad_left("7", 3)`, "  7"},
+				{`pad_left("7", 6, "ab")`, "ababa7"},
+				{`pad_left("1234", 3, "0")`, "1234"},
+				{`pad_right("7", 3, "0")`, "700"},
+				{`pad_right("7", 4, "ab")`, "7aba"},
+				{`format("%d-%s-%.2f-%t", 1, "a", 1.5, true)`, "1-a-1.50-true"},
+				{`format("%v %v %v", [1, "a"], {"a": 1}, nil)`, "[1, a] {a: 1} nil"},
+				{`format("%5s|%-3d|%x", "a", 1, 255)`, "    a|1  |ff"},
+				{`format("100%%")`, "100%"},
+				{`format("%s %s %d %.1f %x", 1, [1, "a"], 2, 3, "ab")`, "1 [1, a] 2 3.0 6162"},
+				{`format("%q %v", "a", 1.5)`, `"a" 1.5`},
+				{`sprintf("%03d", 7)`, "007"},
+				{`to_int("42")`, 42},
+				{`to_int(" -7 ")`, -7},
+				{`to_int(3.9)`, 3},
+				{`to_int(-3.9)`, -3},
+				{`to_int(true)`, 1},
+				{`to_int(5)`, 5},
+				{`to_float("1.5")`, 1.5},
+				{`to_float(2)`, 2.0},
+				{`to_float("1e3")`, 1000.0},
+				{`to_string(1)`, "1"},
+				{`to_string(1.5)`, "1.5"},
+				{`to_string([1, "a"])`, "[1, a]"},
+				{`to_string("a")`, "a"},
+				{`to_int(to_string(12)) + 1`, 13},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testLiteralObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+
+		Convey("Collection test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`split("a,b,c", ",")`, "[a, b, c]"},
+				{`split("abc", "")`, "[a, b, c]"},
+				{`split("", ",")`, "[]"},
+				{`split("a", ",")`, "[a]"},
+				{`chars("abc")`, "[a, b, c]"},
+				{`chars("")`, "[]"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					So(testEval(expected.source).Inspect(), ShouldEqual, expected.result)
+				})
+			}
+		})
+
+		Convey("Error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`split("a")`, "wrong number of arguments for split, Got: 1, Expected: 2"},
+				{`split(1, ",")`, "argument 1 of split must be STRING_OBJECT, Got: INTEGER_OBJECT"},
+				{`split("a", 1)`, "argument 2 of split must be STRING_OBJECT, Got: INTEGER_OBJECT"},
+				{`join("a", ",")`, "argument 1 of join must be ARRAY_OBJECT, Got: STRING_OBJECT"},
+				{`replace("a", "b", 1)`, "argument 3 of replace must be STRING_OBJECT, Got: INTEGER_OBJECT"},
+				{`replace("a", "b", "c", "d")`, "argument 4 of replace must be INTEGER_OBJECT, Got: STRING_OBJECT"},
+				{`trim(1)`, "argument 1 of trim must be STRING_OBJECT, Got: INTEGER_OBJECT"},
+				{`trim("a", "b", "c")`, "wrong number of arguments for trim, Got: 3, Expected: 1 or 2"},
+				{`upper(nil)`, "argument 1 of upper must be STRING_OBJECT, Got: NIL_OBJECT"},
+				{`starts_with("a", 1)`, "argument 2 of starts_with must be STRING_OBJECT, Got: INTEGER_OBJECT"},
+				{`repeat("a", -1)`, "repeat count must not be negative, Got: -1"},
+				{`repeat("ab", 1000000000000)`, "repeat result is too long, Got: 2 * 1000000000000"},
+				{`pad_left("a", "3")`, "argument 2 of pad_left must be INTEGER_OBJECT, Got: STRING_OBJECT"},
+				{`pad_right("a", 3, "")`, "pad_right pad must not be empty"},
+				{`pad_left("a", 1000000000000)`, "pad_left width is too long, Got: 1000000000000"},
+				{`format()`, "wrong number of arguments for format, Got: 0, Expected: at least 1"},
+				{`format(1)`, "argument 1 of format must be STRING_OBJECT, Got: INTEGER_OBJECT"},
+				{`format("%d", "a")`, "argument 2 of format must be INTEGER_OBJECT for %d, Got: STRING_OBJECT"},
+				{`format("%.2f", "a")`, "argument 2 of format must be FLOAT_OBJECT for %.2f, Got: STRING_OBJECT"},
+				{`format("%t", 1)`, "argument 2 of format must be BOOLEAN_OBJECT for %t, Got: INTEGER_OBJECT"},
+				{`format("%d %d", 1)`, "missing argument 3 of format for %d"},
+				{`format("%d", 1, 2)`, "too many arguments for format, Got: 3, Expected: 2"},
+				{`format("%y", 1)`, "unknown verb %y in format"},
+				{`format("100%")`, "missing verb at the end of format \"100%\""},
+				{`to_int("abc")`, `cannot convert "abc" to INTEGER_OBJECT`},
+				{`to_int("1.5")`, `cannot convert "1.5" to INTEGER_OBJECT`},
+				{`to_int("99999999999999999999")`, `cannot convert "99999999999999999999" to INTEGER_OBJECT`},
+				{`to_int(to_float("1e20"))`, "cannot convert 100000000000000000000 to INTEGER_OBJECT, the value is overflow"},
+				{`to_int([])`, "argument 1 of to_int must be STRING_OBJECT, FLOAT_OBJECT or BOOLEAN_OBJECT, Got: ARRAY_OBJECT"},
+				{`to_float("1.5x")`, `cannot convert "1.5x" to FLOAT_OBJECT`},
+				{`to_float(nil)`, "argument 1 of to_float must be STRING_OBJECT or INTEGER_OBJECT, Got: NIL_OBJECT"},
+				{`to_string()`, "wrong number of arguments for to_string, Got: 0, Expected: 1"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testErrorObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+	})
+}
+
+func TestMethodCall(t *testing.T) {
+	Convey("Method call test", t, func() {
+		Convey("Value test", func() {
+			expecteds := []struct {
+				source string
+				result interface{}
+			}{
+				{`"Foo".upper()`, "FOO"},
+				{`"foobar".starts_with("foo")`, true},
+				{`"a,b".split(",").join("-")`, "a-b"},
+				{`"  foo ".trim().len()`, 3},
+				{`let s = "ab"; s.repeat(2)`, "abab"},
+				{`[1, 2, 3].len()`, 3},
+				{`[3, 1, 2].sort().reverse().join("")`, "321"},
+				{`[1, 2, 3].map(func(x) { x * 2 }).reduce(func(a, b) { a + b }, 0)`, 12},
+				{`let a = [1]; a.push(2); len(a)`, 2},
+				{`{"a": 1, "b": 2}.keys().len()`, 2},
+				{`{"len": 5}.len`, 5},
+				{`{"a": 1}.len()`, 1},
+				{`(1).to_string()`, "1"},
+				{`1.to_string()`, "1"},
+				{`1.5.to_int()`, 1},
+				{`let upper = "foo".upper; upper()`, "FOO"},
+				{`map(["a", "b"], "x".ends_with).join(",")`, "false,false"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testLiteralObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+
+		Convey("Inspect test", func() {
+			So(testEval(`"foo".upper`).Inspect(), ShouldEqual, "method upper of STRING_OBJECT")
+		})
+
+		Convey("Error test", func() {
+			expecteds := []struct {
+				source string
+				result string
+			}{
+				{`"a".foo()`, "no method foo on STRING_OBJECT"},
+				{`[].upper()`, "no method upper on ARRAY_OBJECT"},
+				{`nil.len()`, "no method len on NIL_OBJECT"},
+				{`"a".repeat("b")`, "argument 2 of repeat must be INTEGER_OBJECT, Got: STRING_OBJECT"},
+				{`"a".split()`, "wrong number of arguments for split, Got: 1, Expected: 2"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					testErrorObject(testEval(expected.source), expected.result)
+				})
+			}
+		})
+
+		Convey("Registered method test", func() {
+			env := object.NewEnvironment()
+			env.SetMethod(object.STRING_OBJECT, "twice", &object.BuiltIn{
+				Function: func(env *object.Environment, arguments ...object.Object) object.Object {
+					return &object.String{Value: arguments[0].Inspect() + arguments[0].Inspect()}
+				},
+			})
+			env.SetMethod(object.STRING_OBJECT, "upper", &object.BuiltIn{
+				Function: func(env *object.Environment, arguments ...object.Object) object.Object {
+					return &object.String{Value: "overridden"}
+				},
+			})
+
+			So(testEvalWithEnv(`"ab".twice()`, env).Inspect(), ShouldEqual, "abab")
+			So(testEvalWithEnv(`"ab".upper()`, env).Inspect(), ShouldEqual, "overridden")
+			So(testEval(`"ab".upper()`).Inspect(), ShouldEqual, "AB")
+		})
+	})
+}
+
+func TestExecutionLimits(t *testing.T) {
+	Convey("Execution limits test", t, func() {
+		expecteds := []struct {
+			source  string
+			limits  object.Limits
+			kind    string
+			message string
+		}{
+			{`for { }`, object.Limits{MaxSteps: 100}, object.STEP_LIMIT_ERROR, "Exceeded the maximum steps: 100"},
+			{`let a = 0; for { a = a + 1 }`, object.Limits{MaxSteps: 1000}, object.STEP_LIMIT_ERROR, "Exceeded the maximum steps: 1000"},
+			{`func f(n) { f(n + 1) }; f(0)`, object.Limits{MaxCallDepth: 10}, object.CALL_DEPTH_ERROR, "Exceeded the maximum call depth: 10"},
+			{`func f(n) { f(n + 1) }; f(0)`, object.Limits{}, object.CALL_DEPTH_ERROR, "Exceeded the maximum call depth: 1024"},
+			{`let a = [1, 2, 3]; let b = [4, 5];`, object.Limits{MaxAllocations: 4}, object.ALLOCATION_LIMIT_ERROR, "Exceeded the maximum allocations: 4"},
+			{`{"a": 1, "b": 2, "c": 3}`, object.Limits{MaxAllocations: 2}, object.ALLOCATION_LIMIT_ERROR, "Exceeded the maximum allocations: 2"},
+			{`for i in 1..1000000000000 { }`, object.Limits{MaxAllocations: 100}, object.ALLOCATION_LIMIT_ERROR, "Exceeded the maximum allocations: 100"},
+			{`[1, 2] + [3]`, object.Limits{MaxAllocations: 4}, object.ALLOCATION_LIMIT_ERROR, "Exceeded the maximum allocations: 4"},
+			{`try { for { } } catch (e) { e.kind }`, object.Limits{MaxSteps: 100}, object.STEP_LIMIT_ERROR, "Exceeded the maximum steps: 100"},
+			{`map([1], func(x) { for { } })`, object.Limits{MaxSteps: 100}, object.STEP_LIMIT_ERROR, "Exceeded the maximum steps: 100"},
+			{`func f(x) { map([x], f) }; f(1)`, object.Limits{MaxCallDepth: 10}, object.CALL_DEPTH_ERROR, "Exceeded the maximum call depth: 10"},
+			{`map(1..5, func(x) { x })`, object.Limits{MaxAllocations: 6}, object.ALLOCATION_LIMIT_ERROR, "Exceeded the maximum allocations: 6"},
+			{`-9223372036854775807..9223372036854775807`, object.Limits{MaxAllocations: 100}, object.ALLOCATION_LIMIT_ERROR, "Exceeded the maximum allocations: 100"},
+			{`0.0..10000000000000000000000000000000.0`, object.Limits{MaxAllocations: 100}, object.ALLOCATION_LIMIT_ERROR, "Exceeded the maximum allocations: 100"},
+			{`let a = 1..50; 0.0..10000000000000000000000000000000.0`, object.Limits{MaxAllocations: 100}, object.ALLOCATION_LIMIT_ERROR, "Exceeded the maximum allocations: 100"},
+			{`"a".."z"`, object.Limits{MaxAllocations: 10}, object.ALLOCATION_LIMIT_ERROR, "Exceeded the maximum allocations: 10"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				environment := object.NewEnvironment()
+				environment.SetLimits(expected.limits)
+
+				evaluated := testEvalWithEnv(expected.source, environment)
+
+				testErrorObject(evaluated, expected.message)
+
+				Convey(runMessage("Error kind should be equals %s", expected.kind), func() {
+					So(evaluated.(*object.Error).Kind, ShouldEqual, expected.kind)
+				})
+			})
+		}
+
+		Convey("Catch the limit error", func() {
+			expecteds := []struct {
+				source string
+				limits object.Limits
+				result string
+			}{
+				{`func f() { f() }; try { f() } catch (e) { e.kind }`, object.Limits{MaxCallDepth: 10}, object.CALL_DEPTH_ERROR},
+				{`try { 1..100 } catch (e) { e.kind }`, object.Limits{MaxAllocations: 10}, object.ALLOCATION_LIMIT_ERROR},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+					environment := object.NewEnvironment()
+					environment.SetLimits(expected.limits)
+
+					testStringObject(testEvalWithEnv(expected.source, environment), expected.result)
+				})
+			}
+		})
+
+		Convey("Cancel by context", func() {
+			cancelled, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			timeout, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			expecteds := []struct {
+				context context.Context
+				message string
+			}{
+				{cancelled, "Execution cancelled: context canceled"},
+				{timeout, "Execution cancelled: context deadline exceeded"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d", index), func() {
+					environment := object.NewEnvironment()
+					environment.SetContext(expected.context)
+
+					evaluated := testEvalWithEnv(`for { }`, environment)
+
+					testErrorObject(evaluated, expected.message)
+
+					So(evaluated.(*object.Error).Kind, ShouldEqual, object.CANCEL_ERROR)
+				})
+			}
+		})
+	})
+}
+
+func TestLargeProgram(t *testing.T) {
+	Convey("Large program test", t, func() {
+		// The numbers of variables and arguments are larger than the operands of bytecode
+		numbers := func(format string, count int) string {
+			parts := make([]string, count)
+			for index := range parts {
+				parts[index] = fmt.Sprintf(format, index, index)
+			}
+
+			return strings.Join(parts, "")
+		}
+
+		expecteds := []struct {
+			name   string
+			source string
+			result string
+		}{
+			{"300 locals", "func f() { " + numbers("let v%d = %d; ", 300) + "v299 }; f()", "299"},
+			{"300 arguments", "let a = []; push(a" + numbers(", %[1]d", 300) + "); [len(a), a[299]]", "[300, 299]"},
+			{"70000 globals", numbers("let g%d = %d;\n", 70000) + "g69999", "69999"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Name: %s", index, expected.name), func() {
+				So(testEval(expected.source).Inspect(), ShouldEqual, expected.result)
+			})
+		}
+	})
+}
+
+func TestBlockStatement(t *testing.T) {
+	Convey("Block statement test", t, func() {
+		expecteds := []struct {
+			source      string
+			returnValue interface{}
+		}{
+			{"let a = 1;", 1},
+			{"let a = 1; let b = 2;", 2},
+
+			{"let a = 1.1;", 1.1},
+			{"let a = 1.1; let b = 2.2;", 2.2},
+
 			{`let a = "foo";`, "foo"},
 			{`let a = "foo"; let b = "bar"`, "bar"},
 
@@ -1201,6 +2604,18 @@ func TestBlockStatement(t *testing.T) {
 }
 
 //
+// Returns the environment with testFail function which always returns the runtime error
+func testFailEnvironment() *object.Environment {
+	environment := object.NewEnvironment()
+	environment.SetBuiltIn("testFail", &object.BuiltIn{
+		Function: func(env *object.Environment, arguments ...object.Object) object.Object {
+			return object.NewError(object.RUNTIME_ERROR, "failed by %s", arguments[0].Inspect())
+		},
+	})
+
+	return environment
+}
+
 func testEval(source string) object.Object {
 	return testEvalWithEnv(source, object.NewEnvironment())
 }
@@ -1210,7 +2625,7 @@ func testEvalWithEnv(source string, environment *object.Environment) object.Obje
 	theParser := parser.NewParser(theLexer)
 	theProgarm := theParser.Parse()
 
-	return Eval(theProgarm, environment)
+	return testEngine(theProgarm, environment)
 }
 
 func testLiteralObject(obj object.Object, expected interface{}) {
@@ -0,0 +1,58 @@
+package evaluator
+
+import (
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// The operator functions below work on evaluated objects only, they are shared
+// with the vm package so both engines give the same result for the same code
+
+// InfixOperator applies the infix operator like +, ==, && to left and right objects
+func InfixOperator(left object.Object, operator string, right object.Object, env *object.Environment) object.Object {
+	return evalInfixExpression(left, operator, right, env)
+}
+
+// PrefixOperator applies the prefix operator like !, -, + to right object
+func PrefixOperator(operator string, right object.Object) object.Object {
+	return evalPrefixOperatorExpression(operator, right)
+}
+
+// IndexOperator returns the item of array, hash or string by index like left[index]
+func IndexOperator(left object.Object, index object.Object) object.Object {
+	return evalIndexOperatorExpression(left, index)
+}
+
+// DotOperator returns the item of hash or module, or the method of object by name like left.item
+func DotOperator(left object.Object, item object.Object, env *object.Environment) object.Object {
+	return evalDotOperatorExpression(left, item, env)
+}
+
+// TemplateOperator concatenates the evaluated parts of template string like "a ${b}"
+func TemplateOperator(parts []object.Object) object.Object {
+	return evalTemplateOperatorExpression(parts)
+}
+
+// RangeOperator returns the array of start..end
+func RangeOperator(start object.Object, end object.Object, env *object.Environment) object.Object {
+	return evalRangeOperatorExpression(start, end, env)
+}
+
+// AssignIndexOperator sets the value into array or hash like left[index] = value
+func AssignIndexOperator(left object.Object, index object.Object, value object.Object) object.Object {
+	return evalAssignIndexOperatorExpression(left, index, value)
+}
+
+// AssignDotOperator sets the value into hash like left.item = value
+func AssignDotOperator(left object.Object, item object.Object, value object.Object) object.Object {
+	return evalAssignDotOperatorExpression(left, item, value)
+}
+
+// CallFunction calls the function or built-in function with arguments, it is the function caller of environment
+func CallFunction(env *object.Environment, function object.Object, arguments []object.Object) object.Object {
+	return applyFunction(env, function, arguments, nil)
+}
+
+// IsTruthy reports the object is true or not in condition like if (object) { ... }
+func IsTruthy(obj object.Object) bool {
+	return isTruthy(obj)
+}
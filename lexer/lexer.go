@@ -1,7 +1,9 @@
 package lexer
 
 import (
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/zeuxisoo/go-skrip/pkg/helper"
 	"github.com/zeuxisoo/go-skrip/token"
@@ -9,10 +11,13 @@ import (
 
 type Lexer struct {
 	source          string
-	currentChar     rune // current character
-	currentPosition int  // position of current character
-	nextPosition    int  // position after current character (greater than 1)
-	currentLine     int  // position of current line
+	file            string // file name of source, it will be attached to token position
+	currentChar     rune   // current character, it is decoded from UTF-8
+	currentPosition int    // byte offset of current character
+	nextPosition    int    // byte offset after current character
+	currentLine     int    // position of current line
+	currentColumn   int    // position of current character in current line, it counts the characters instead of bytes
+	templates       []int  // brace depth of each open interpolation, e.g. "${ {"a": 1}.a }"
 }
 
 func NewLexer(source string) *Lexer {
@@ -26,6 +31,11 @@ func NewLexer(source string) *Lexer {
 	return lexer
 }
 
+// SetFile sets the file name of source for token position
+func (l *Lexer) SetFile(file string) {
+	l.file = file
+}
+
 //
 func (l *Lexer) NextToken() token.Token {
 	var theToken token.Token
@@ -44,6 +54,8 @@ func (l *Lexer) NextToken() token.Token {
 		return l.NextToken()
 	}
 
+	start := l.position()
+
 	switch l.currentChar {
 	case '=':
 		// if next char is '=', it should be "==" operator
@@ -57,6 +69,15 @@ func (l *Lexer) NextToken() token.Token {
 				Type:    token.EQ,
 				Literal: string(oldCurrentChar) + string(l.currentChar), // text: ==
 			}
+		} else if l.nextChar() == '>' {
+			oldCurrentChar := l.currentChar
+
+			l.readChar()
+
+			theToken = token.Token{
+				Type:    token.ARROW,
+				Literal: string(oldCurrentChar) + string(l.currentChar), // text: =>
+			}
 		} else {
 			theToken = l.newToken(token.ASSIGN)
 		}
@@ -71,9 +92,24 @@ func (l *Lexer) NextToken() token.Token {
 	case ')':
 		theToken = l.newToken(token.RIGHT_PARENTHESIS)
 	case '{':
+		if len(l.templates) > 0 {
+			l.templates[len(l.templates)-1]++
+		}
+
 		theToken = l.newToken(token.LEFT_BRACE)
 	case '}':
-		theToken = l.newToken(token.RIGHT_BRACE)
+		// The "}" of interpolation continues the template string, e.g. "${name} text"
+		if depth := len(l.templates); depth > 0 && l.templates[depth-1] == 0 {
+			l.templates = l.templates[:depth-1]
+
+			theToken = l.readString(token.TEMPLATE_MIDDLE, token.TEMPLATE_TAIL)
+		} else {
+			if depth > 0 {
+				l.templates[depth-1]--
+			}
+
+			theToken = l.newToken(token.RIGHT_BRACE)
+		}
 	case '[':
 		theToken = l.newToken(token.LEFT_BRACKET)
 	case ']':
@@ -124,10 +160,9 @@ func (l *Lexer) NextToken() token.Token {
 			theToken = l.newToken(token.GT)
 		}
 	case '"':
-		theToken = token.Token{
-			Type:    token.STRING,
-			Literal: l.readString(),
-		}
+		theToken = l.readString(token.TEMPLATE_HEAD, token.STRING)
+	case '`':
+		theToken = l.readRawString()
 	case '&':
 		if l.nextChar() == '&' {
 			oldCurrentChar := l.currentChar
@@ -152,7 +187,7 @@ func (l *Lexer) NextToken() token.Token {
 				Literal: string(oldCurrentChar) + string(l.currentChar), // text: ||
 			}
 		} else {
-			theToken = l.newToken(token.ILLEGAL)
+			theToken = l.newToken(token.PIPE)
 		}
 	case ':':
 		theToken = l.newToken(token.COLON)
@@ -166,6 +201,15 @@ func (l *Lexer) NextToken() token.Token {
 				Type:    token.RANGE,
 				Literal: string(oldCurrentChar) + string(l.currentChar), // text: ..
 			}
+
+			if l.nextChar() == '.' {
+				l.readChar()
+
+				theToken = token.Token{
+					Type:    token.ELLIPSIS,
+					Literal: theToken.Literal + string(l.currentChar), // text: ...
+				}
+			}
 		} else {
 			theToken = l.newToken(token.DOT)
 		}
@@ -174,19 +218,16 @@ func (l *Lexer) NextToken() token.Token {
 		theToken.Type = token.EOF
 	default:
 		if helper.IsLetter(l.currentChar) {
-			lineNumber := l.currentLine
 			identifier := l.readIdentifier()
 
-			return token.Token{
-				Type:       token.FindKeywordType(identifier),
-				Literal:    identifier,
-				LineNumber: lineNumber,
-			}
+			return l.locateToken(token.Token{
+				Type:    token.FindKeywordType(identifier),
+				Literal: identifier,
+			}, start)
 		}
 
 		if helper.IsDigit(l.currentChar) {
 			theToken.Literal = l.readNumber()
-			theToken.LineNumber = l.currentLine
 
 			switch len(strings.Split(theToken.Literal, ".")) {
 			case 1: // e.g. 12, 13
@@ -194,40 +235,44 @@ func (l *Lexer) NextToken() token.Token {
 			case 2: // e.g. 12.00, 13.77
 				theToken.Type = token.FLOAT
 			default:
-				return l.newIllegalToken(theToken.Literal)
+				return l.locateToken(l.newIllegalToken(theToken.Literal), start)
 			}
 
-			return theToken
+			return l.locateToken(theToken, start)
 		}
 
-		theToken = l.newIllegalToken(string(l.currentChar))
+		theToken = l.newIllegalToken(l.source[l.currentPosition:l.nextPosition])
 	}
 
 	l.readChar()
 
-	theToken.LineNumber = l.currentLine
-
-	return theToken
+	return l.locateToken(theToken, start)
 }
 
 //
 func (l *Lexer) readChar() {
+	// Move to the beginning of next line when leaving a newline
+	// Otherwise move to next column
+	if l.currentChar == '\n' {
+		l.currentLine++
+		l.currentColumn = 1
+	} else {
+		l.currentColumn++
+	}
+
 	// Reset to 0 when next position greater than source length (for EOF char)
-	// Otherwise set next position to current position
+	// Otherwise decode the character at next position, it may be multiple bytes
+	size := 1
+
 	if l.nextPosition >= len(l.source) {
 		l.currentChar = 0
 	} else {
-		l.currentChar = rune(l.source[l.nextPosition])
-	}
-
-	// Increase the current line no when encountering a newline
-	if l.currentChar == '\n' {
-		l.currentLine++
+		l.currentChar, size = utf8.DecodeRuneInString(l.source[l.nextPosition:])
 	}
 
 	l.currentPosition = l.nextPosition
 
-	l.nextPosition++
+	l.nextPosition += size
 }
 
 func (l *Lexer) skipWhitespace() {
@@ -281,8 +326,8 @@ func (l *Lexer) readNumber() string {
 	startPosition := l.currentPosition
 
 	for helper.IsDigit(l.currentChar) || helper.IsDot(l.currentChar) {
-		// When meet "." it may range "..", if range "..", return current read value
-		if l.currentChar == '.' && l.nextChar() == '.' {
+		// When meet "." it may range ".." or method call like 1.to_string(), return current read value
+		if l.currentChar == '.' && helper.IsDigit(l.nextChar()) == false {
 			return l.source[startPosition:l.currentPosition]
 		}
 
@@ -292,51 +337,196 @@ func (l *Lexer) readNumber() string {
 	return l.source[startPosition:l.currentPosition]
 }
 
-func (l *Lexer) readString() string {
-	startPosition := l.currentPosition + 1 // skip start "
+// Read the string from the start " or the end } of interpolation, the token type is headType when the string
+// is stopped by interpolation "${", otherwise it is endType. The escape sequences are replaced by their values
+func (l *Lexer) readString(headType token.Type, endType token.Type) token.Token {
+	var text strings.Builder
+
+	startPosition := l.currentPosition
+	illegal := ""
+
+	l.readChar() // skip start " or }
+
+	for {
+		switch {
+		case l.currentChar == 0:
+			return l.newIllegalToken(l.source[startPosition:l.currentPosition])
+		case l.currentChar == '"':
+			if illegal != "" {
+				return l.newIllegalToken(illegal)
+			}
+
+			return token.Token{
+				Type:    endType,
+				Literal: text.String(),
+			}
+		case l.currentChar == '$' && l.nextChar() == '{':
+			l.readChar() // stop at {, it will be skipped by NextToken
+
+			if illegal != "" {
+				return l.newIllegalToken(illegal)
+			}
+
+			l.templates = append(l.templates, 0)
+
+			return token.Token{
+				Type:    headType,
+				Literal: text.String(),
+			}
+		case l.currentChar == '\\':
+			escapePosition := l.currentPosition
+
+			value, ok := l.readEscape()
+			if ok == false && illegal == "" {
+				illegal = l.source[escapePosition:l.nextPosition]
+			}
+
+			text.WriteString(value)
+		default:
+			text.WriteString(l.source[l.currentPosition:l.nextPosition])
+		}
 
-	for l.currentChar != 0 {
 		l.readChar()
+	}
+}
+
+// Read the escape sequence like \n, \x41, \u00e9 or \u{1F600} and stop at the last character of it
+func (l *Lexer) readEscape() (string, bool) {
+	l.readChar() // skip \
 
-		// Continue when meet quote escape \"
-		if l.currentChar == '\\' && l.nextChar() == '"' {
+	switch l.currentChar {
+	case 'n':
+		return "\n", true
+	case 't':
+		return "\t", true
+	case 'r':
+		return "\r", true
+	case '0':
+		return "\x00", true
+	case 'b':
+		return "\b", true
+	case 'f':
+		return "\f", true
+	case 'v':
+		return "\v", true
+	case '\\', '"', '\'', '$':
+		return string(l.currentChar), true
+	case 'x':
+		code, ok := l.readHexDigits(2)
+
+		return string([]byte{byte(code)}), ok
+	case 'u':
+		var (
+			code rune
+			ok   bool
+		)
+
+		if l.nextChar() == '{' {
 			l.readChar()
+
+			code, ok = l.readHexDigits(-1)
+
+			if l.nextChar() != '}' {
+				return "", false
+			}
+
 			l.readChar()
+		} else {
+			code, ok = l.readHexDigits(4)
 		}
 
-		// Break when meet end of "
-		if l.currentChar == '"' {
-			break
+		if ok == false || utf8.ValidRune(code) == false {
+			return "", false
 		}
+
+		return string(code), true
+	default:
+		return "", false
+	}
+}
+
+// Read the hex digits after current character, the count -1 means reading until the non hex digit (at most 6)
+func (l *Lexer) readHexDigits(count int) (rune, bool) {
+	startPosition := l.nextPosition
+
+	for (count == -1 || l.nextPosition-startPosition < count) && helper.IsHexDigit(l.nextChar()) {
+		l.readChar()
 	}
 
-	text := l.source[startPosition:l.currentPosition]
+	digits := l.source[startPosition:l.nextPosition]
+	if digits == "" || (count != -1 && len(digits) != count) || len(digits) > 6 {
+		return 0, false
+	}
 
-	// No limit for replacements \" to "
-	return strings.Replace(text, "\\\"", "\"", -1)
+	code, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return rune(code), true
+}
+
+// Read the raw string between backticks, it may be multi-line and the escape sequences are kept
+func (l *Lexer) readRawString() token.Token {
+	startPosition := l.currentPosition
+
+	l.readChar() // skip start `
+
+	for l.currentChar != '`' {
+		if l.currentChar == 0 {
+			return l.newIllegalToken(l.source[startPosition:l.currentPosition])
+		}
+
+		l.readChar()
+	}
+
+	return token.Token{
+		Type:    token.STRING,
+		Literal: l.source[startPosition+1 : l.currentPosition],
+	}
 }
 
 func (l *Lexer) newToken(tokenType token.Type) token.Token {
 	return token.Token{
-		Type:       tokenType,
-		Literal:    string(l.currentChar),
-		LineNumber: l.currentLine,
+		Type:    tokenType,
+		Literal: l.source[l.currentPosition:l.nextPosition],
 	}
 }
 
 func (l *Lexer) newIllegalToken(literal string) token.Token {
 	return token.Token{
-		Type:       token.ILLEGAL,
-		Literal:    literal,
-		LineNumber: l.currentLine,
+		Type:    token.ILLEGAL,
+		Literal: literal,
+	}
+}
+
+// Set the start position and the end position (current position) of token
+func (l *Lexer) locateToken(theToken token.Token, start token.Position) token.Token {
+	theToken.LineNumber = start.Line
+	theToken.Column = start.Column
+	theToken.Offset = start.Offset
+	theToken.File = start.File
+	theToken.End = l.position()
+
+	return theToken
+}
+
+func (l *Lexer) position() token.Position {
+	return token.Position{
+		File:   l.file,
+		Line:   l.currentLine,
+		Column: l.currentColumn,
+		Offset: l.currentPosition,
 	}
 }
 
 func (l *Lexer) nextChar() rune {
 	// e.g. End of file will return 0
-	if l.nextPosition > len(l.source) {
+	if l.nextPosition >= len(l.source) {
 		return 0
 	}
 
-	return rune(l.source[l.nextPosition])
+	char, _ := utf8.DecodeRuneInString(l.source[l.nextPosition:])
+
+	return char
 }
@@ -242,6 +242,54 @@ func TestLexerKeywords(t *testing.T) {
 	})
 }
 
+func TestLexerImportKeywords(t *testing.T) {
+	Convey("Import keywords testing", t, func() {
+		source := `
+			import "lib/math.sk" as math;
+		`
+
+		expectedTokens := []expectedToken{
+			{token.IMPORT, "import"},
+			{token.STRING, "lib/math.sk"},
+			{token.AS, "as"},
+			{token.IDENTIFIER, "math"},
+			{token.SEMICOLON, ";"},
+			{token.EOF, ""},
+		}
+
+		testToken(NewLexer(source), expectedTokens)
+	})
+}
+
+func TestLexerTryKeywords(t *testing.T) {
+	Convey("Try keywords testing", t, func() {
+		source := `
+			try { throw "foo"; } catch (e) {} finally {}
+		`
+
+		expectedTokens := []expectedToken{
+			{token.TRY, "try"},
+			{token.LEFT_BRACE, "{"},
+			{token.THROW, "throw"},
+			{token.STRING, "foo"},
+			{token.SEMICOLON, ";"},
+			{token.RIGHT_BRACE, "}"},
+			{token.CATCH, "catch"},
+			{token.LEFT_PARENTHESIS, "("},
+			{token.IDENTIFIER, "e"},
+			{token.RIGHT_PARENTHESIS, ")"},
+			{token.LEFT_BRACE, "{"},
+			{token.RIGHT_BRACE, "}"},
+			{token.FINALLY, "finally"},
+			{token.LEFT_BRACE, "{"},
+			{token.RIGHT_BRACE, "}"},
+			{token.EOF, ""},
+		}
+
+		testToken(NewLexer(source), expectedTokens)
+	})
+}
+
 func TestStringEscapeQuote(t *testing.T) {
 	Convey("String escape quote", t, func() {
 		source := `
@@ -256,6 +304,171 @@ func TestStringEscapeQuote(t *testing.T) {
 	})
 }
 
+func TestStringEscapeSequence(t *testing.T) {
+	Convey("String escape sequence", t, func() {
+		expecteds := []struct {
+			source  string
+			literal string
+		}{
+			{`"a\nb"`, "a\nb"},
+			{`"a\tb\rc"`, "a\tb\rc"},
+			{`"a\\b"`, `a\b`},
+			{`"\'\$\0"`, "'$\x00"},
+			{`"\x41\x7a"`, "Az"},
+			{`"caf\u00e9"`, "café"},
+			{`"\u{1F600}"`, "😀"},
+			{`"\${name}"`, "${name}"},
+			{`"café"`, "café"},
+			{"\"a\nb\"", "a\nb"},
+			{"`raw \\n ${name}`", "raw \\n ${name}"},
+			{"`a\nb`", "a\nb"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(fmt.Sprintf("Running %d, Source: %s", index, expected.source), func() {
+				theToken := NewLexer(expected.source).NextToken()
+
+				So(theToken.Type, ShouldEqual, token.STRING)
+				So(theToken.Literal, ShouldEqual, expected.literal)
+			})
+		}
+	})
+}
+
+func TestStringIllegal(t *testing.T) {
+	Convey("String illegal", t, func() {
+		expecteds := []struct {
+			source  string
+			literal string
+		}{
+			{`"a\qb"`, `\q`},
+			{`"\x4"`, `\x4`},
+			{`"\u00e"`, `\u00e`},
+			{`"\u{110000}"`, `\u{110000}`},
+			{`"\u{41"`, `\u{41`},
+			{`"abc`, `"abc`},
+			{"`abc", "`abc"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(fmt.Sprintf("Running %d, Source: %s", index, expected.source), func() {
+				theToken := NewLexer(expected.source).NextToken()
+
+				So(theToken.Type, ShouldEqual, token.ILLEGAL)
+				So(theToken.Literal, ShouldEqual, expected.literal)
+			})
+		}
+	})
+}
+
+func TestStringTemplate(t *testing.T) {
+	Convey("String template", t, func() {
+		source := `"a ${b} c ${ {"d": "${e}"}.d } f" + "${g}"`
+
+		expectedTokens := []expectedToken{
+			{token.TEMPLATE_HEAD, "a "},
+			{token.IDENTIFIER, "b"},
+			{token.TEMPLATE_MIDDLE, " c "},
+			{token.LEFT_BRACE, "{"},
+			{token.STRING, "d"},
+			{token.COLON, ":"},
+			{token.TEMPLATE_HEAD, ""},
+			{token.IDENTIFIER, "e"},
+			{token.TEMPLATE_TAIL, ""},
+			{token.RIGHT_BRACE, "}"},
+			{token.DOT, "."},
+			{token.IDENTIFIER, "d"},
+			{token.TEMPLATE_TAIL, " f"},
+			{token.PLUS, "+"},
+			{token.TEMPLATE_HEAD, ""},
+			{token.IDENTIFIER, "g"},
+			{token.TEMPLATE_TAIL, ""},
+			{token.EOF, ""},
+		}
+
+		testToken(NewLexer(source), expectedTokens)
+	})
+}
+
+func TestLexerUnicode(t *testing.T) {
+	Convey("Unicode testing", t, func() {
+		source := `let 名前 = "héllo"; café_1 + λ; é → 1`
+
+		expectedTokens := []expectedToken{
+			{token.LET, "let"},
+			{token.IDENTIFIER, "名前"},
+			{token.ASSIGN, "="},
+			{token.STRING, "héllo"},
+			{token.SEMICOLON, ";"},
+			{token.IDENTIFIER, "café_1"},
+			{token.PLUS, "+"},
+			{token.IDENTIFIER, "λ"},
+			{token.SEMICOLON, ";"},
+			{token.IDENTIFIER, "é"},
+			{token.ILLEGAL, "→"},
+			{token.INT, "1"},
+			{token.EOF, ""},
+		}
+
+		testToken(NewLexer(source), expectedTokens)
+
+		Convey("The column counts the characters", func() {
+			theLexer := NewLexer("\"é\" + ü")
+			theLexer.NextToken()
+			theLexer.NextToken()
+
+			theToken := theLexer.NextToken()
+
+			So(theToken.Literal, ShouldEqual, "ü")
+			So(theToken.Position(), ShouldResemble, token.Position{Line: 1, Column: 7, Offset: 7})
+			So(theToken.End, ShouldResemble, token.Position{Line: 1, Column: 8, Offset: 9})
+		})
+	})
+}
+
+func TestTokenPosition(t *testing.T) {
+	Convey("Token position testing", t, func() {
+		source := "let a = 1;\n  a >= \"foo\"\n/* comment */ a..b"
+
+		expecteds := []struct {
+			literal string
+			start   token.Position
+			end     token.Position
+		}{
+			{"let", token.Position{File: "main.sk", Line: 1, Column: 1, Offset: 0}, token.Position{File: "main.sk", Line: 1, Column: 4, Offset: 3}},
+			{"a", token.Position{File: "main.sk", Line: 1, Column: 5, Offset: 4}, token.Position{File: "main.sk", Line: 1, Column: 6, Offset: 5}},
+			{"=", token.Position{File: "main.sk", Line: 1, Column: 7, Offset: 6}, token.Position{File: "main.sk", Line: 1, Column: 8, Offset: 7}},
+			{"1", token.Position{File: "main.sk", Line: 1, Column: 9, Offset: 8}, token.Position{File: "main.sk", Line: 1, Column: 10, Offset: 9}},
+			{";", token.Position{File: "main.sk", Line: 1, Column: 10, Offset: 9}, token.Position{File: "main.sk", Line: 1, Column: 11, Offset: 10}},
+			{"a", token.Position{File: "main.sk", Line: 2, Column: 3, Offset: 13}, token.Position{File: "main.sk", Line: 2, Column: 4, Offset: 14}},
+			{">=", token.Position{File: "main.sk", Line: 2, Column: 5, Offset: 15}, token.Position{File: "main.sk", Line: 2, Column: 7, Offset: 17}},
+			{"foo", token.Position{File: "main.sk", Line: 2, Column: 8, Offset: 18}, token.Position{File: "main.sk", Line: 2, Column: 13, Offset: 23}},
+			{"a", token.Position{File: "main.sk", Line: 3, Column: 15, Offset: 38}, token.Position{File: "main.sk", Line: 3, Column: 16, Offset: 39}},
+			{"..", token.Position{File: "main.sk", Line: 3, Column: 16, Offset: 39}, token.Position{File: "main.sk", Line: 3, Column: 18, Offset: 41}},
+			{"b", token.Position{File: "main.sk", Line: 3, Column: 18, Offset: 41}, token.Position{File: "main.sk", Line: 3, Column: 19, Offset: 42}},
+		}
+
+		theLexer := NewLexer(source)
+		theLexer.SetFile("main.sk")
+
+		for index, expected := range expecteds {
+			theToken := theLexer.NextToken()
+
+			Convey(fmt.Sprintf("Running %d, Literal: %s", index, expected.literal), func() {
+				So(theToken.Literal, ShouldEqual, expected.literal)
+				So(theToken.LineNumber, ShouldEqual, expected.start.Line)
+				So(theToken.Position(), ShouldResemble, expected.start)
+				So(theToken.End, ShouldResemble, expected.end)
+			})
+		}
+
+		Convey("Position string should be file:line:column", func() {
+			So(expecteds[5].start.String(), ShouldEqual, "main.sk:2:3")
+			So(token.Position{Line: 2, Column: 3}.String(), ShouldEqual, "2:3")
+		})
+	})
+}
+
 func TestSkipComment(t *testing.T) {
 	Convey("Skip comment", t, func() {
 		source := `
@@ -301,6 +514,85 @@ func TestRange(t *testing.T) {
 	})
 }
 
+func TestFunctionParameters(t *testing.T) {
+	Convey("Function parameters", t, func() {
+		source := `func(a, b = 2, ...rest) {}; f(b: 3);`
+
+		expectedTokens := []expectedToken{
+			{token.FUNCTION, "func"},
+			{token.LEFT_PARENTHESIS, "("},
+			{token.IDENTIFIER, "a"},
+			{token.COMMA, ","},
+			{token.IDENTIFIER, "b"},
+			{token.ASSIGN, "="},
+			{token.INT, "2"},
+			{token.COMMA, ","},
+			{token.ELLIPSIS, "..."},
+			{token.IDENTIFIER, "rest"},
+			{token.RIGHT_PARENTHESIS, ")"},
+			{token.LEFT_BRACE, "{"},
+			{token.RIGHT_BRACE, "}"},
+			{token.SEMICOLON, ";"},
+			{token.IDENTIFIER, "f"},
+			{token.LEFT_PARENTHESIS, "("},
+			{token.IDENTIFIER, "b"},
+			{token.COLON, ":"},
+			{token.INT, "3"},
+			{token.RIGHT_PARENTHESIS, ")"},
+		}
+
+		testToken(NewLexer(source), expectedTokens)
+	})
+}
+
+func TestMatch(t *testing.T) {
+	Convey("Match", t, func() {
+		source := `match a { 1 | 2 => b }`
+
+		expectedTokens := []expectedToken{
+			{token.MATCH, "match"},
+			{token.IDENTIFIER, "a"},
+			{token.LEFT_BRACE, "{"},
+			{token.INT, "1"},
+			{token.PIPE, "|"},
+			{token.INT, "2"},
+			{token.ARROW, "=>"},
+			{token.IDENTIFIER, "b"},
+			{token.RIGHT_BRACE, "}"},
+		}
+
+		testToken(NewLexer(source), expectedTokens)
+	})
+}
+
+func TestStruct(t *testing.T) {
+	Convey("Struct", t, func() {
+		source := `struct Point { x, y = 0; func f() { self } }`
+
+		expectedTokens := []expectedToken{
+			{token.STRUCT, "struct"},
+			{token.IDENTIFIER, "Point"},
+			{token.LEFT_BRACE, "{"},
+			{token.IDENTIFIER, "x"},
+			{token.COMMA, ","},
+			{token.IDENTIFIER, "y"},
+			{token.ASSIGN, "="},
+			{token.INT, "0"},
+			{token.SEMICOLON, ";"},
+			{token.FUNCTION, "func"},
+			{token.IDENTIFIER, "f"},
+			{token.LEFT_PARENTHESIS, "("},
+			{token.RIGHT_PARENTHESIS, ")"},
+			{token.LEFT_BRACE, "{"},
+			{token.IDENTIFIER, "self"},
+			{token.RIGHT_BRACE, "}"},
+			{token.RIGHT_BRACE, "}"},
+		}
+
+		testToken(NewLexer(source), expectedTokens)
+	})
+}
+
 // Sub method for test case
 func testToken(theLexer *Lexer, expectedTokens []expectedToken) {
 	for index, currentExpectedToken := range expectedTokens {
@@ -0,0 +1,47 @@
+package object
+
+import (
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+// StackFrame is a function call in the call stack
+type StackFrame struct {
+	Function string         // the function name in call expression
+	Position token.Position // the location of call expression
+}
+
+// CallStack keeps the calling functions of a program, the last frame is the innermost call
+type CallStack struct {
+	frames []StackFrame
+}
+
+func NewCallStack() *CallStack {
+	return &CallStack{
+		frames: []StackFrame{},
+	}
+}
+
+func (s *CallStack) Push(frame StackFrame) {
+	s.frames = append(s.frames, frame)
+}
+
+func (s *CallStack) Pop() {
+	if len(s.frames) > 0 {
+		s.frames = s.frames[:len(s.frames)-1]
+	}
+}
+
+func (s *CallStack) Depth() int {
+	return len(s.frames)
+}
+
+// Frames returns the copy of frames from the innermost call to the outermost call
+func (s *CallStack) Frames() []StackFrame {
+	frames := make([]StackFrame, 0, len(s.frames))
+
+	for index := len(s.frames) - 1; index >= 0; index-- {
+		frames = append(frames, s.frames[index])
+	}
+
+	return frames
+}
@@ -0,0 +1,98 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Capability is the group of built-in functions which touch the outside of program
+type Capability string
+
+// Capabilities
+const (
+	IO_CAPABILITY   Capability = "io"   // write to stdout or stderr
+	FS_CAPABILITY   Capability = "fs"   // read or write the file system
+	NET_CAPABILITY  Capability = "net"  // access the network
+	EXEC_CAPABILITY Capability = "exec" // run the external process
+	TIME_CAPABILITY Capability = "time" // read the clock or sleep
+	ENV_CAPABILITY  Capability = "env"  // read or write the environment variables
+)
+
+// Capabilities is the list of all capabilities
+var Capabilities = []Capability{
+	IO_CAPABILITY,
+	FS_CAPABILITY,
+	NET_CAPABILITY,
+	EXEC_CAPABILITY,
+	TIME_CAPABILITY,
+	ENV_CAPABILITY,
+}
+
+// ParseCapabilities parses the comma separated capability names, e.g. "fs,net"
+func ParseCapabilities(names string) ([]Capability, error) {
+	capabilities := []Capability{}
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		capability, ok := findCapability(name)
+		if ok == false {
+			return nil, fmt.Errorf("Unknown capability: %s", name)
+		}
+
+		capabilities = append(capabilities, capability)
+	}
+
+	return capabilities, nil
+}
+
+func findCapability(name string) (Capability, bool) {
+	for _, capability := range Capabilities {
+		if string(capability) == name {
+			return capability, true
+		}
+	}
+
+	return "", false
+}
+
+// SetCapabilities grants the capabilities to program only, the nil means all capabilities are granted
+func (env *Environment) SetCapabilities(capabilities []Capability) {
+	if capabilities == nil {
+		env.shared.capabilities = nil
+
+		return
+	}
+
+	env.shared.capabilities = make(map[Capability]bool, len(capabilities))
+
+	for _, capability := range capabilities {
+		env.shared.capabilities[capability] = true
+	}
+}
+
+// Granted returns true when the capability is granted to program, the empty capability is always granted
+func (env *Environment) Granted(capability Capability) bool {
+	if capability == "" || env.shared.capabilities == nil {
+		return true
+	}
+
+	return env.shared.capabilities[capability]
+}
+
+// CheckPermission returns error when the capability of built-in function is not granted
+func (env *Environment) CheckPermission(builtIn *BuiltIn) *Error {
+	return env.CheckCapability(builtIn.Capability)
+}
+
+// CheckCapability returns error when the capability is not granted, e.g. import requires the fs capability
+func (env *Environment) CheckCapability(capability Capability) *Error {
+	if env.Granted(capability) == true {
+		return nil
+	}
+
+	return NewError(PERMISSION_ERROR, "Permission denied, the %s capability is not granted", capability)
+}
@@ -0,0 +1,46 @@
+package object
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCapabilities(t *testing.T) {
+	Convey("Capabilities test", t, func() {
+		Convey("Parse capabilities", func() {
+			capabilities, err := ParseCapabilities("io, env,,fs")
+
+			So(err, ShouldBeNil)
+			So(capabilities, ShouldResemble, []Capability{IO_CAPABILITY, ENV_CAPABILITY, FS_CAPABILITY})
+
+			capabilities, err = ParseCapabilities("")
+
+			So(err, ShouldBeNil)
+			So(capabilities, ShouldBeEmpty)
+
+			_, err = ParseCapabilities("io,foo")
+
+			So(err.Error(), ShouldEqual, "Unknown capability: foo")
+		})
+
+		Convey("Check permission", func() {
+			env := NewEnvironment()
+			builtIn := &BuiltIn{Capability: NET_CAPABILITY}
+
+			So(env.CheckPermission(builtIn), ShouldBeNil)
+			So(env.CheckPermission(&BuiltIn{}), ShouldBeNil)
+
+			env.SetCapabilities([]Capability{IO_CAPABILITY})
+
+			So(env.CheckPermission(&BuiltIn{}), ShouldBeNil)
+			So(env.CheckPermission(&BuiltIn{Capability: IO_CAPABILITY}), ShouldBeNil)
+			So(env.CheckPermission(builtIn).Kind, ShouldEqual, PERMISSION_ERROR)
+			So(env.CheckPermission(builtIn).Message, ShouldEqual, "Permission denied, the net capability is not granted")
+
+			env.SetCapabilities(nil)
+
+			So(env.CheckPermission(builtIn), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,426 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The struct tag for the key name of struct field in hash, e.g. `skrip:"name"` or `skrip:"-"` to skip the field
+const convertTagName = "skrip"
+
+var (
+	objectType         = reflect.TypeOf((*Object)(nil)).Elem()
+	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// FromGo converts the Go value to object by reflection,
+// the slice and array will be converted to array, the map and struct will be converted to hash
+func FromGo(value interface{}) (Object, error) {
+	if value == nil {
+		return &Nil{}, nil
+	}
+
+	return fromGoValue(reflect.ValueOf(value))
+}
+
+// ToGo converts the object to the Go value which the target pointer pointed to by reflection,
+// the target can be the empty interface, the array will be []interface{} and the hash will be map[string]interface{}
+func ToGo(obj Object, target interface{}) error {
+	value := reflect.ValueOf(target)
+
+	if value.Kind() != reflect.Ptr || value.IsNil() == true {
+		return fmt.Errorf("target must be a non-nil pointer, Got: %T", target)
+	}
+
+	return toGoValue(obj, value.Elem())
+}
+
+func fromGoValue(value reflect.Value) (Object, error) {
+	if value.IsValid() == false {
+		return &Nil{}, nil
+	}
+
+	if value.Type().Implements(objectType) == true && value.Kind() != reflect.Interface {
+		if value.Kind() == reflect.Ptr && value.IsNil() == true {
+			return &Nil{}, nil
+		}
+
+		return value.Interface().(Object), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() == true {
+			return &Nil{}, nil
+		}
+
+		return fromGoValue(value.Elem())
+	case reflect.Bool:
+		return &Boolean{Value: value.Bool()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Integer{Value: value.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("cannot convert %d to integer, the value is overflow", value.Uint())
+		}
+
+		return &Integer{Value: int64(value.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Float{Value: value.Float()}, nil
+	case reflect.String:
+		return &String{Value: value.String()}, nil
+	case reflect.Slice, reflect.Array:
+		return fromGoSlice(value)
+	case reflect.Map:
+		return fromGoMap(value)
+	case reflect.Struct:
+		return fromGoStruct(value)
+	default:
+		return nil, fmt.Errorf("cannot convert Go value of type %s to object", value.Type())
+	}
+}
+
+func fromGoSlice(value reflect.Value) (Object, error) {
+	if value.Kind() == reflect.Slice && value.IsNil() == true {
+		return &Nil{}, nil
+	}
+
+	elements := make([]Object, value.Len())
+
+	for index := 0; index < value.Len(); index++ {
+		element, err := fromGoValue(value.Index(index))
+		if err != nil {
+			return nil, err
+		}
+
+		elements[index] = element
+	}
+
+	return &Array{Elements: elements}, nil
+}
+
+func fromGoMap(value reflect.Value) (Object, error) {
+	if value.IsNil() == true {
+		return &Nil{}, nil
+	}
+
+	hash := newConvertedHash()
+
+	// The order of map is random, sort the keys to make the order of hash stable
+	keys := value.MapKeys()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	for _, key := range keys {
+		hashKey, err := fromGoValue(key)
+		if err != nil {
+			return nil, err
+		}
+
+		hashValue, err := fromGoValue(value.MapIndex(key))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := setConvertedHashPair(hash, hashKey, hashValue); err != nil {
+			return nil, err
+		}
+	}
+
+	return hash, nil
+}
+
+func fromGoStruct(value reflect.Value) (Object, error) {
+	hash := newConvertedHash()
+	valueType := value.Type()
+
+	for index := 0; index < valueType.NumField(); index++ {
+		name, ok := fieldName(valueType.Field(index))
+		if ok == false {
+			continue
+		}
+
+		hashValue, err := fromGoValue(value.Field(index))
+		if err != nil {
+			return nil, err
+		}
+
+		setConvertedHashPair(hash, &String{Value: name}, hashValue)
+	}
+
+	return hash, nil
+}
+
+func toGoValue(obj Object, target reflect.Value) error {
+	// The missing object is the same as nil, e.g. the element of array which created by Go
+	if obj == nil {
+		obj = &Nil{}
+	}
+
+	// Keep the object when the target is object, e.g. Object or *Hash
+	if target.Type() != emptyInterfaceType && reflect.TypeOf(obj).AssignableTo(target.Type()) == true {
+		target.Set(reflect.ValueOf(obj))
+
+		return nil
+	}
+
+	if _, ok := obj.(*Nil); ok {
+		target.Set(reflect.Zero(target.Type()))
+
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Interface:
+		if target.NumMethod() > 0 {
+			break
+		}
+
+		value, err := toGoInterface(obj)
+		if err != nil {
+			return err
+		}
+
+		target.Set(reflect.ValueOf(value))
+
+		return nil
+	case reflect.Ptr:
+		value := reflect.New(target.Type().Elem())
+
+		if err := toGoValue(obj, value.Elem()); err != nil {
+			return err
+		}
+
+		target.Set(value)
+
+		return nil
+	case reflect.Bool:
+		if boolean, ok := obj.(*Boolean); ok {
+			target.SetBool(boolean.Value)
+
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if integer, ok := obj.(*Integer); ok {
+			if target.OverflowInt(integer.Value) == true {
+				return fmt.Errorf("cannot convert %s to %s, the value is overflow", integer.Inspect(), target.Type())
+			}
+
+			target.SetInt(integer.Value)
+
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if integer, ok := obj.(*Integer); ok {
+			if integer.Value < 0 || target.OverflowUint(uint64(integer.Value)) == true {
+				return fmt.Errorf("cannot convert %s to %s, the value is overflow", integer.Inspect(), target.Type())
+			}
+
+			target.SetUint(uint64(integer.Value))
+
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch number := obj.(type) {
+		case *Float:
+			target.SetFloat(number.Value)
+
+			return nil
+		case *Integer:
+			target.SetFloat(float64(number.Value))
+
+			return nil
+		}
+	case reflect.String:
+		if str, ok := obj.(*String); ok {
+			target.SetString(str.Value)
+
+			return nil
+		}
+	case reflect.Slice:
+		if array, ok := obj.(*Array); ok {
+			slice := reflect.MakeSlice(target.Type(), len(array.Elements), len(array.Elements))
+
+			if err := toGoElements(array, slice); err != nil {
+				return err
+			}
+
+			target.Set(slice)
+
+			return nil
+		}
+	case reflect.Array:
+		if array, ok := obj.(*Array); ok {
+			if len(array.Elements) != target.Len() {
+				return fmt.Errorf("cannot convert array of length %d to %s", len(array.Elements), target.Type())
+			}
+
+			return toGoElements(array, target)
+		}
+	case reflect.Map:
+		if hash, ok := obj.(*Hash); ok {
+			return toGoMap(hash, target)
+		}
+	case reflect.Struct:
+		if hash, ok := obj.(*Hash); ok {
+			return toGoStruct(hash, target)
+		}
+	}
+
+	return fmt.Errorf("cannot convert %s to %s", obj.Type(), target.Type())
+}
+
+func toGoElements(array *Array, target reflect.Value) error {
+	for index, element := range array.Elements {
+		if err := toGoValue(element, target.Index(index)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toGoMap(hash *Hash, target reflect.Value) error {
+	targetType := target.Type()
+	result := reflect.MakeMapWithSize(targetType, len(hash.Order))
+
+	for _, hashKey := range hash.Order {
+		pair := hash.Pairs[hashKey]
+
+		key := reflect.New(targetType.Key()).Elem()
+		if err := toGoValue(pair.Key, key); err != nil {
+			return err
+		}
+
+		value := reflect.New(targetType.Elem()).Elem()
+		if err := toGoValue(pair.Value, value); err != nil {
+			return err
+		}
+
+		result.SetMapIndex(key, value)
+	}
+
+	target.Set(result)
+
+	return nil
+}
+
+func toGoStruct(hash *Hash, target reflect.Value) error {
+	targetType := target.Type()
+
+	for index := 0; index < targetType.NumField(); index++ {
+		name, ok := fieldName(targetType.Field(index))
+		if ok == false {
+			continue
+		}
+
+		key := &String{Value: name}
+
+		pair, ok := hash.Pairs[key.HashKey()]
+		if ok == false {
+			continue
+		}
+
+		if err := toGoValue(pair.Value, target.Field(index)); err != nil {
+			return fmt.Errorf("field %s: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Convert the object to the basic Go value for empty interface
+func toGoInterface(obj Object) (interface{}, error) {
+	switch obj := obj.(type) {
+	case nil, *Nil:
+		return nil, nil
+	case *Boolean:
+		return obj.Value, nil
+	case *Integer:
+		return obj.Value, nil
+	case *Float:
+		return obj.Value, nil
+	case *String:
+		return obj.Value, nil
+	case *Array:
+		values := make([]interface{}, len(obj.Elements))
+
+		for index, element := range obj.Elements {
+			value, err := toGoInterface(element)
+			if err != nil {
+				return nil, err
+			}
+
+			values[index] = value
+		}
+
+		return values, nil
+	case *Hash:
+		values := make(map[string]interface{}, len(obj.Order))
+
+		for _, hashKey := range obj.Order {
+			pair := obj.Pairs[hashKey]
+
+			value, err := toGoInterface(pair.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			values[pair.Key.Inspect()] = value
+		}
+
+		return values, nil
+	default:
+		return nil, fmt.Errorf("cannot convert %s to Go value", obj.Type())
+	}
+}
+
+// Returns the key name of struct field in hash, the unexported field and the field tagged by "-" will be skipped
+func fieldName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+
+	tag := field.Tag.Get(convertTagName)
+	if tag == "-" {
+		return "", false
+	}
+
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name, true
+	}
+
+	return field.Name, true
+}
+
+func newConvertedHash() *Hash {
+	return &Hash{
+		Order: []HashKey{},
+		Pairs: make(map[HashKey]HashPair),
+	}
+}
+
+func setConvertedHashPair(hash *Hash, key Object, value Object) error {
+	hashable, ok := key.(Hashable)
+	if ok == false {
+		return fmt.Errorf("cannot use %s as hash key", key.Type())
+	}
+
+	hashKey := hashable.HashKey()
+
+	if _, ok := hash.Pairs[hashKey]; ok == false {
+		hash.Order = append(hash.Order, hashKey)
+	}
+
+	hash.Pairs[hashKey] = HashPair{
+		Key:   key,
+		Value: value,
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type convertProfile struct {
+	Name    string `skrip:"name"`
+	Age     int    `skrip:"age"`
+	Tags    []string
+	Score   *float64 `skrip:"score"`
+	Ignored string   `skrip:"-"`
+	private string
+}
+
+func TestFromGo(t *testing.T) {
+	Convey("From Go test", t, func() {
+		score := 9.5
+
+		expecteds := []struct {
+			value   interface{}
+			inspect string
+			kind    ObjectType
+		}{
+			{nil, "nil", NIL_OBJECT},
+			{true, "true", BOOLEAN_OBJECT},
+			{int8(-8), "-8", INTEGER_OBJECT},
+			{uint32(32), "32", INTEGER_OBJECT},
+			{1.5, "1.5", FLOAT_OBJECT},
+			{"foo", "foo", STRING_OBJECT},
+			{&score, "9.5", FLOAT_OBJECT},
+			{(*int)(nil), "nil", NIL_OBJECT},
+			{[]int{1, 2}, "[1, 2]", ARRAY_OBJECT},
+			{[2]string{"a", "b"}, "[a, b]", ARRAY_OBJECT},
+			{[]interface{}{1, "a", nil, []bool{true}}, "[1, a, nil, [true]]", ARRAY_OBJECT},
+			{map[string]int{"b": 2, "a": 1}, "{a: 1, b: 2}", HASH_OBJECT},
+			{map[int]string{1: "a"}, "{1: a}", HASH_OBJECT},
+			{&String{Value: "object"}, "object", STRING_OBJECT},
+			{
+				convertProfile{Name: "foo", Age: 8, Tags: []string{"x"}, Score: &score, Ignored: "y", private: "z"},
+				"{Tags: [x], age: 8, name: foo, score: 9.5}",
+				HASH_OBJECT,
+			},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Type: %T", index, expected.value), func() {
+				obj, err := FromGo(expected.value)
+
+				So(err, ShouldBeNil)
+				So(obj.Type(), ShouldEqual, expected.kind)
+				So(obj.Inspect(), ShouldEqual, expected.inspect)
+			})
+		}
+
+		Convey("Keep the order of struct fields", func() {
+			obj, _ := FromGo(convertProfile{})
+
+			keys := []string{}
+			for _, key := range obj.(*Hash).Order {
+				keys = append(keys, obj.(*Hash).Pairs[key].Key.Inspect())
+			}
+
+			So(keys, ShouldResemble, []string{"name", "age", "Tags", "score"})
+		})
+
+		Convey("Unsupported value", func() {
+			_, err := FromGo(make(chan int))
+			So(err.Error(), ShouldEqual, "cannot convert Go value of type chan int to object")
+
+			_, err = FromGo([]func(){func() {}})
+			So(err.Error(), ShouldEqual, "cannot convert Go value of type func() to object")
+
+			_, err = FromGo(uint64(math.MaxUint64))
+			So(err.Error(), ShouldEqual, "cannot convert 18446744073709551615 to integer, the value is overflow")
+
+			_, err = FromGo([]uint{math.MaxInt64 + 1})
+			So(err.Error(), ShouldEqual, "cannot convert 9223372036854775808 to integer, the value is overflow")
+		})
+	})
+}
+
+func TestToGo(t *testing.T) {
+	Convey("To Go test", t, func() {
+		Convey("Basic value", func() {
+			var (
+				boolean bool
+				integer int
+				small   uint8
+				float   float64
+				str     string
+				pointer *int
+			)
+
+			So(ToGo(&Boolean{Value: true}, &boolean), ShouldBeNil)
+			So(ToGo(&Integer{Value: 10}, &integer), ShouldBeNil)
+			So(ToGo(&Integer{Value: 255}, &small), ShouldBeNil)
+			So(ToGo(&Integer{Value: 2}, &float), ShouldBeNil)
+			So(ToGo(&String{Value: "foo"}, &str), ShouldBeNil)
+			So(ToGo(&Integer{Value: 3}, &pointer), ShouldBeNil)
+
+			So(boolean, ShouldBeTrue)
+			So(integer, ShouldEqual, 10)
+			So(small, ShouldEqual, 255)
+			So(float, ShouldEqual, 2.0)
+			So(str, ShouldEqual, "foo")
+			So(*pointer, ShouldEqual, 3)
+
+			So(ToGo(&Nil{}, &pointer), ShouldBeNil)
+			So(pointer, ShouldBeNil)
+
+			So(ToGo(nil, &integer), ShouldBeNil)
+			So(integer, ShouldEqual, 0)
+
+			So(ToGo(nil, &pointer), ShouldBeNil)
+			So(pointer, ShouldBeNil)
+
+			var values interface{}
+
+			So(ToGo(&Array{Elements: []Object{nil, &Integer{Value: 1}}}, &values), ShouldBeNil)
+			So(values, ShouldResemble, []interface{}{nil, int64(1)})
+		})
+
+		Convey("Collection value", func() {
+			var (
+				slice  []int
+				array  [2]string
+				hash   map[string]float64
+				value  interface{}
+				object Object
+			)
+
+			So(ToGo(&Array{Elements: []Object{&Integer{Value: 1}, &Integer{Value: 2}}}, &slice), ShouldBeNil)
+			So(slice, ShouldResemble, []int{1, 2})
+
+			So(ToGo(&Array{Elements: []Object{&String{Value: "a"}, &String{Value: "b"}}}, &array), ShouldBeNil)
+			So(array, ShouldResemble, [2]string{"a", "b"})
+
+			source, _ := FromGo(map[string]interface{}{"a": 1, "b": 2.5})
+
+			So(ToGo(source, &hash), ShouldBeNil)
+			So(hash, ShouldResemble, map[string]float64{"a": 1, "b": 2.5})
+
+			So(ToGo(source, &value), ShouldBeNil)
+			So(value, ShouldResemble, map[string]interface{}{"a": int64(1), "b": 2.5})
+
+			So(ToGo(source, &object), ShouldBeNil)
+			So(object, ShouldEqual, source)
+		})
+
+		Convey("Struct value", func() {
+			score := 7.5
+			source, _ := FromGo(map[string]interface{}{
+				"name":    "foo",
+				"age":     8,
+				"Tags":    []string{"x", "y"},
+				"score":   score,
+				"Ignored": "bar",
+			})
+
+			var profile convertProfile
+
+			So(ToGo(source, &profile), ShouldBeNil)
+			So(profile.Name, ShouldEqual, "foo")
+			So(profile.Age, ShouldEqual, 8)
+			So(profile.Tags, ShouldResemble, []string{"x", "y"})
+			So(*profile.Score, ShouldEqual, score)
+			So(profile.Ignored, ShouldEqual, "")
+		})
+
+		Convey("Conversion error", func() {
+			var (
+				integer int
+				small   int8
+				natural uint
+				profile convertProfile
+			)
+
+			So(ToGo(&Integer{Value: 1}, integer).Error(), ShouldEqual, "target must be a non-nil pointer, Got: int")
+			So(ToGo(&String{Value: "a"}, &integer).Error(), ShouldEqual, "cannot convert STRING_OBJECT to int")
+			So(ToGo(&Integer{Value: 128}, &small).Error(), ShouldEqual, "cannot convert 128 to int8, the value is overflow")
+			So(ToGo(&Integer{Value: -1}, &natural).Error(), ShouldEqual, "cannot convert -1 to uint, the value is overflow")
+
+			source, _ := FromGo(map[string]interface{}{"age": "eight"})
+
+			So(ToGo(source, &profile).Error(), ShouldEqual, "field age: cannot convert STRING_OBJECT to int")
+		})
+	})
+}
+
+func runMessage(format string, values ...interface{}) string {
+	return fmt.Sprintf(format, values...)
+}
@@ -1,26 +1,92 @@
 package object
 
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+)
+
+// Environment is safe for concurrent use, but the objects in it are not,
+// so an object should not be shared between the programs which running in parallel
 type Environment struct {
+	mutex  sync.RWMutex
 	store  map[string]Object
 	parent *Environment
+	file   string
+	shared *shared
+	engine interface{} // the state of execution engine which kept between evaluations, e.g. the globals of vm
+	block  bool        // the scope of block like catch, the assignment of outer variable is not shadowed
+}
+
+// Caller calls the function object with arguments, it is provided by the execution engine,
+// so the built-in function can call back the function of script, e.g. map(array, func(x) { x * 2 })
+type Caller func(env *Environment, function Object, arguments []Object) Object
+
+// shared is the state of a program, it is shared by all environments of the program and its modules
+type shared struct {
+	modules  *ModuleCache
+	calls    *CallStack
+	mutex    sync.RWMutex // guards the built-in functions and methods which may be registered during evaluation
+	builtIns map[string]*BuiltIn
+	methods  map[ObjectType]map[string]*BuiltIn
+	stdout   io.Writer
+	stderr   io.Writer
+	context  context.Context
+	limits   Limits
+	usage    usage
+
+	capabilities map[Capability]bool // nil means all capabilities are granted
+
+	caller Caller
 }
 
 func NewEnvironment() *Environment {
 	return &Environment{
 		store:  make(map[string]Object),
 		parent: nil,
+		shared: &shared{
+			modules:  NewModuleCache(),
+			calls:    NewCallStack(),
+			builtIns: make(map[string]*BuiltIn),
+			methods:  make(map[ObjectType]map[string]*BuiltIn),
+			stdout:   os.Stdout,
+			stderr:   os.Stderr,
+		},
 	}
 }
 
 func NewEnclosedEnvironment(parent *Environment) *Environment {
 	environment := NewEnvironment()
 	environment.parent = parent
+	environment.shared = parent.shared
+
+	return environment
+}
+
+// NewBlockEnvironment creates the scope of block, the bindings of it are invisible outside the block,
+// but the variables of enclosing scope can be assigned like the block is not scoped
+func NewBlockEnvironment(parent *Environment) *Environment {
+	environment := NewEnclosedEnvironment(parent)
+	environment.block = true
+
+	return environment
+}
+
+// NewModuleEnvironment creates an isolated environment for the module file,
+// it cannot see the bindings of importer but share the same state of program like module cache
+func NewModuleEnvironment(importer *Environment, file string) *Environment {
+	environment := NewEnvironment()
+	environment.file = file
+	environment.shared = importer.shared
 
 	return environment
 }
 
 func (env *Environment) Get(name string) (Object, bool) {
+	env.mutex.RLock()
 	obj, ok := env.store[name]
+	env.mutex.RUnlock()
 
 	// Try get from parent store when current store is not available
 	if ok == false && env.parent != nil {
@@ -31,7 +97,137 @@ func (env *Environment) Get(name string) (Object, bool) {
 }
 
 func (env *Environment) Set(name string, value Object) Object {
+	env.mutex.Lock()
 	env.store[name] = value
+	env.mutex.Unlock()
 
 	return value
 }
+
+// Assign sets the existing variable of the block scopes, otherwise it sets the variable to current store like Set,
+// so the variable of outer function is shadowed in function scope
+func (env *Environment) Assign(name string, value Object) Object {
+	env.mutex.RLock()
+	_, ok := env.store[name]
+	env.mutex.RUnlock()
+
+	if ok == false && env.block == true {
+		if _, found := env.parent.Get(name); found == true {
+			return env.parent.Assign(name, value)
+		}
+	}
+
+	return env.Set(name, value)
+}
+
+// File returns the script file path of current environment, empty when the code is not from file
+func (env *Environment) File() string {
+	if env.file == "" && env.parent != nil {
+		return env.parent.File()
+	}
+
+	return env.file
+}
+
+func (env *Environment) SetFile(file string) {
+	env.file = file
+}
+
+func (env *Environment) EngineState() interface{} {
+	return env.engine
+}
+
+func (env *Environment) SetEngineState(state interface{}) {
+	env.engine = state
+}
+
+func (env *Environment) Modules() *ModuleCache {
+	return env.shared.modules
+}
+
+func (env *Environment) CallStack() *CallStack {
+	return env.shared.calls
+}
+
+// BuiltIn returns the built-in function which registered to the program only
+func (env *Environment) BuiltIn(name string) (*BuiltIn, bool) {
+	env.shared.mutex.RLock()
+	defer env.shared.mutex.RUnlock()
+
+	builtIn, ok := env.shared.builtIns[name]
+
+	return builtIn, ok
+}
+
+// SetBuiltIn registers the built-in function to the program, it will override the default built-in function,
+// the capability of built-in function will be checked when it is called
+func (env *Environment) SetBuiltIn(name string, builtIn *BuiltIn) {
+	env.shared.mutex.Lock()
+	defer env.shared.mutex.Unlock()
+
+	env.shared.builtIns[name] = builtIn
+}
+
+// Method returns the method of object type which registered to the program only
+func (env *Environment) Method(objectType ObjectType, name string) (*BuiltIn, bool) {
+	env.shared.mutex.RLock()
+	defer env.shared.mutex.RUnlock()
+
+	method, ok := env.shared.methods[objectType][name]
+
+	return method, ok
+}
+
+// SetMethod registers the method of object type to the program, the receiver is passed as the first argument,
+// it will override the default method
+func (env *Environment) SetMethod(objectType ObjectType, name string, method *BuiltIn) {
+	env.shared.mutex.Lock()
+	defer env.shared.mutex.Unlock()
+
+	if env.shared.methods[objectType] == nil {
+		env.shared.methods[objectType] = make(map[string]*BuiltIn)
+	}
+
+	env.shared.methods[objectType][name] = method
+}
+
+// Stdout returns the writer of print functions, default is os.Stdout
+func (env *Environment) Stdout() io.Writer {
+	return env.shared.stdout
+}
+
+func (env *Environment) SetStdout(writer io.Writer) {
+	env.shared.stdout = writer
+}
+
+// Stderr returns the writer of error output, default is os.Stderr
+func (env *Environment) Stderr() io.Writer {
+	return env.shared.stderr
+}
+
+func (env *Environment) SetStderr(writer io.Writer) {
+	env.shared.stderr = writer
+}
+
+// Caller returns the function caller of current execution engine
+func (env *Environment) Caller() Caller {
+	return env.shared.caller
+}
+
+func (env *Environment) SetCaller(caller Caller) {
+	env.shared.caller = caller
+}
+
+// Call calls the function object like the call expression in script, it is used by built-in function
+func (env *Environment) Call(function Object, arguments ...Object) Object {
+	if env.shared.caller == nil {
+		return NewError(RUNTIME_ERROR, "Cannot call %s without execution engine", function.Type())
+	}
+
+	// The empty function returns nothing
+	if result := env.shared.caller(env, function, arguments); result != nil {
+		return result
+	}
+
+	return &Nil{}
+}
@@ -0,0 +1,96 @@
+package object
+
+import (
+	"context"
+)
+
+// DefaultMaxCallDepth is the maximum call depth when it is not limited
+const DefaultMaxCallDepth = 1024
+
+// Limits is the execution limits of program, the zero value means unlimited except the call depth,
+// because the deep recursion will crash the process, it will be limited by DefaultMaxCallDepth
+type Limits struct {
+	MaxSteps       int64 // the count of evaluated nodes in evaluator or executed instructions in vm
+	MaxCallDepth   int   // the count of nested function calls
+	MaxAllocations int64 // the total count of allocated elements of array and hash
+}
+
+// usage counts the resources which used by program for limits
+type usage struct {
+	steps       int64
+	allocations int64
+}
+
+// SetContext sets the context of program, the program will be stopped when the context is cancelled
+func (env *Environment) SetContext(ctx context.Context) {
+	env.shared.context = ctx
+}
+
+func (env *Environment) Context() context.Context {
+	if env.shared.context == nil {
+		return context.Background()
+	}
+
+	return env.shared.context
+}
+
+// SetLimits sets the execution limits of program and resets the used steps and allocations
+func (env *Environment) SetLimits(limits Limits) {
+	env.shared.limits = limits
+	env.shared.usage = usage{}
+}
+
+func (env *Environment) Limits() Limits {
+	return env.shared.limits
+}
+
+// MaxCallDepth returns the maximum call depth of program, it is DefaultMaxCallDepth when it is not limited
+func (env *Environment) MaxCallDepth() int {
+	if env.shared.limits.MaxCallDepth > 0 {
+		return env.shared.limits.MaxCallDepth
+	}
+
+	return DefaultMaxCallDepth
+}
+
+// Step counts an evaluation step, it returns error when the program is cancelled or the steps exceeded
+func (env *Environment) Step() *Error {
+	shared := env.shared
+
+	if shared.context != nil {
+		select {
+		case <-shared.context.Done():
+			return NewError(CANCEL_ERROR, "Execution cancelled: %s", shared.context.Err())
+		default:
+		}
+	}
+
+	shared.usage.steps++
+
+	if shared.limits.MaxSteps > 0 && shared.usage.steps > shared.limits.MaxSteps {
+		return NewError(STEP_LIMIT_ERROR, "Exceeded the maximum steps: %d", shared.limits.MaxSteps)
+	}
+
+	return nil
+}
+
+// Allocate counts the allocated elements of array or hash, it returns error when the allocations exceeded
+func (env *Environment) Allocate(count int64) *Error {
+	shared := env.shared
+
+	// Compare before counting, the huge count may overflow the usage
+	if shared.limits.MaxAllocations > 0 && count > shared.limits.MaxAllocations-shared.usage.allocations {
+		shared.usage.allocations = shared.limits.MaxAllocations
+
+		return NewError(ALLOCATION_LIMIT_ERROR, "Exceeded the maximum allocations: %d", shared.limits.MaxAllocations)
+	}
+
+	shared.usage.allocations += count
+
+	return nil
+}
+
+// CallDepthError returns the error when the call depth exceeded
+func (env *Environment) CallDepthError() *Error {
+	return NewError(CALL_DEPTH_ERROR, "Exceeded the maximum call depth: %d", env.MaxCallDepth())
+}
@@ -15,6 +15,13 @@ const (
 	FUNCTION_OBJECT     = "FUNCTION_OBJECT"
 	BREAK_OBJECT        = "BREAK_OBJECT"
 	CONTINUE_OBJECT     = "CONTINUE_OBJECT"
+	MODULE_OBJECT       = "MODULE_OBJECT"
+	CAUGHT_ERROR_OBJECT = "CAUGHT_ERROR_OBJECT"
+	BOUND_METHOD_OBJECT = "BOUND_METHOD_OBJECT"
+	STRUCT_OBJECT       = "STRUCT_OBJECT"
+	INSTANCE_OBJECT     = "INSTANCE_OBJECT"
+
+	COMPILED_FUNCTION_OBJECT = "COMPILED_FUNCTION_OBJECT"
 )
 
 //
@@ -0,0 +1,17 @@
+package object
+
+// BoundMethod is the method of built-in type which bound to the receiver, e.g. "abc".upper,
+// the receiver is passed as the first argument when it is called
+type BoundMethod struct {
+	Name     string
+	Receiver Object
+	Method   *BuiltIn
+}
+
+func (b *BoundMethod) Type() ObjectType {
+	return BOUND_METHOD_OBJECT
+}
+
+func (b *BoundMethod) Inspect() string {
+	return "method " + b.Name + " of " + string(b.Receiver.Type())
+}
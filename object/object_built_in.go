@@ -1,9 +1,55 @@
 package object
 
+import (
+	"fmt"
+	"reflect"
+)
+
 type BuiltInFunction func(env *Environment, arguments ...Object) Object
 
 type BuiltIn struct {
-	Function BuiltInFunction
+	Function   BuiltInFunction
+	Capability Capability // the capability which required to call this function, empty means no requirement
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// NewBuiltInFunc wraps the Go function to built-in function by reflection, e.g. func(a string, b int) (string, error),
+// the arguments and result will be converted by ToGo and FromGo, the returned error will be raised as runtime error
+func NewBuiltInFunc(name string, function interface{}) (*BuiltIn, error) {
+	value := reflect.ValueOf(function)
+	if value.Kind() != reflect.Func || value.IsNil() == true {
+		return nil, fmt.Errorf("%s must be a function, Got: %T", name, function)
+	}
+
+	functionType := value.Type()
+
+	switch {
+	case functionType.NumOut() > 2:
+		return nil, fmt.Errorf("%s must return at most 2 values, Got: %s", name, functionType)
+	case functionType.NumOut() == 2 && functionType.Out(1) != errorType:
+		return nil, fmt.Errorf("%s must return error as the second value, Got: %s", name, functionType)
+	}
+
+	builtIn := &BuiltIn{
+		Function: func(env *Environment, arguments ...Object) (result Object) {
+			// The panic of Go function should not stop the program
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					result = NewError(RUNTIME_ERROR, "panic in %s: %v", name, recovered)
+				}
+			}()
+
+			parameters, err := builtInFuncParameters(name, functionType, arguments)
+			if err != nil {
+				return err
+			}
+
+			return builtInFuncResult(name, functionType, value.Call(parameters))
+		},
+	}
+
+	return builtIn, nil
 }
 
 func (b *BuiltIn) Type() ObjectType {
@@ -13,3 +59,72 @@ func (b *BuiltIn) Type() ObjectType {
 func (b *BuiltIn) Inspect() string {
 	return "built-in function"
 }
+
+// Convert the arguments to the parameters of Go function
+func builtInFuncParameters(name string, functionType reflect.Type, arguments []Object) ([]reflect.Value, *Error) {
+	numIn := functionType.NumIn()
+
+	if functionType.IsVariadic() == true {
+		if len(arguments) < numIn-1 {
+			return nil, NewError(
+				RUNTIME_ERROR,
+				"wrong number of arguments for %s, Got: %d, Expected: at least %d", name, len(arguments), numIn-1,
+			)
+		}
+	} else if len(arguments) != numIn {
+		return nil, NewError(
+			RUNTIME_ERROR,
+			"wrong number of arguments for %s, Got: %d, Expected: %d", name, len(arguments), numIn,
+		)
+	}
+
+	parameters := make([]reflect.Value, len(arguments))
+
+	for index, argument := range arguments {
+		var parameterType reflect.Type
+
+		// The rest arguments are the elements of variadic parameter
+		if functionType.IsVariadic() == true && index >= numIn-1 {
+			parameterType = functionType.In(numIn - 1).Elem()
+		} else {
+			parameterType = functionType.In(index)
+		}
+
+		parameter := reflect.New(parameterType)
+
+		if err := ToGo(argument, parameter.Interface()); err != nil {
+			return nil, NewError(RUNTIME_ERROR, "invalid argument %d for %s: %s", index+1, name, err)
+		}
+
+		parameters[index] = parameter.Elem()
+	}
+
+	return parameters, nil
+}
+
+// Convert the results of Go function to object, the nil will be returned when the function has no result
+func builtInFuncResult(name string, functionType reflect.Type, results []reflect.Value) Object {
+	if len(results) == 0 {
+		return &Nil{}
+	}
+
+	// The last result is error
+	if last := results[len(results)-1]; functionType.Out(len(results)-1) == errorType {
+		if last.IsNil() == false {
+			return NewError(RUNTIME_ERROR, "%s", last.Interface().(error).Error())
+		}
+
+		results = results[:len(results)-1]
+	}
+
+	if len(results) == 0 {
+		return &Nil{}
+	}
+
+	result, err := fromGoValue(results[0])
+	if err != nil {
+		return NewError(RUNTIME_ERROR, "invalid result of %s: %s", name, err)
+	}
+
+	return result
+}
@@ -0,0 +1,134 @@
+package object
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewBuiltInFunc(t *testing.T) {
+	Convey("New built-in function test", t, func() {
+		functions := map[string]interface{}{
+			"repeat": func(text string, count int) string {
+				return strings.Repeat(text, count)
+			},
+			"divide": func(a float64, b float64) (float64, error) {
+				if b == 0 {
+					return 0, errors.New("division by zero")
+				}
+
+				return a / b, nil
+			},
+			"join": func(separator string, values ...string) string {
+				return strings.Join(values, separator)
+			},
+			"check": func(value bool) error {
+				if value == false {
+					return errors.New("check failed")
+				}
+
+				return nil
+			},
+			"nothing": func() {},
+			"names": func(profile map[string]int) []string {
+				names := []string{}
+				for name := range profile {
+					names = append(names, name)
+				}
+
+				return names
+			},
+			"crash": func() int {
+				panic("boom")
+			},
+		}
+
+		builtIns := map[string]*BuiltIn{}
+
+		for name, function := range functions {
+			builtIn, err := NewBuiltInFunc(name, function)
+
+			So(err, ShouldBeNil)
+
+			builtIns[name] = builtIn
+		}
+
+		call := func(name string, values ...interface{}) Object {
+			arguments := make([]Object, len(values))
+
+			for index, value := range values {
+				arguments[index], _ = FromGo(value)
+			}
+
+			return builtIns[name].Function(NewEnvironment(), arguments...)
+		}
+
+		Convey("Call function", func() {
+			expecteds := []struct {
+				result  Object
+				inspect string
+			}{
+				{call("repeat", "ab", 3), "ababab"},
+				{call("divide", 3, 2), "1.5"},
+				{call("join", ","), ""},
+				{call("join", ",", "a", "b", "c"), "a,b,c"},
+				{call("check", true), "nil"},
+				{call("nothing"), "nil"},
+				{call("names", map[string]int{"a": 1}), "[a]"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d", index), func() {
+					So(expected.result.Type(), ShouldNotEqual, ERROR_OBJECT)
+					So(expected.result.Inspect(), ShouldEqual, expected.inspect)
+				})
+			}
+		})
+
+		Convey("Call function with error", func() {
+			expecteds := []struct {
+				result  Object
+				message string
+			}{
+				{call("repeat", "ab"), "wrong number of arguments for repeat, Got: 1, Expected: 2"},
+				{call("join"), "wrong number of arguments for join, Got: 0, Expected: at least 1"},
+				{call("repeat", "ab", "3"), "invalid argument 2 for repeat: cannot convert STRING_OBJECT to int"},
+				{call("join", ",", "a", 1), "invalid argument 3 for join: cannot convert INTEGER_OBJECT to string"},
+				{call("divide", 1, 0), "division by zero"},
+				{call("check", false), "check failed"},
+				{call("crash"), "panic in crash: boom"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d", index), func() {
+					err, ok := expected.result.(*Error)
+
+					So(ok, ShouldBeTrue)
+					So(err.Kind, ShouldEqual, RUNTIME_ERROR)
+					So(err.Message, ShouldEqual, expected.message)
+				})
+			}
+		})
+
+		Convey("Invalid function", func() {
+			expecteds := []struct {
+				function interface{}
+				message  string
+			}{
+				{1, "foo must be a function, Got: int"},
+				{func() (int, int, error) { return 0, 0, nil }, "foo must return at most 2 values, Got: func() (int, int, error)"},
+				{func() (int, int) { return 0, 0 }, "foo must return error as the second value, Got: func() (int, int)"},
+			}
+
+			for index, expected := range expecteds {
+				Convey(runMessage("Running: %d", index), func() {
+					_, err := NewBuiltInFunc("foo", expected.function)
+
+					So(err.Error(), ShouldEqual, expected.message)
+				})
+			}
+		})
+	})
+}
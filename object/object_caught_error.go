@@ -0,0 +1,14 @@
+package object
+
+// CaughtError is the error which caught by catch block, it will not be propagated like error
+type CaughtError struct {
+	Error *Error
+}
+
+func (c *CaughtError) Type() ObjectType {
+	return CAUGHT_ERROR_OBJECT
+}
+
+func (c *CaughtError) Inspect() string {
+	return c.Error.Inspect()
+}
@@ -0,0 +1,52 @@
+package object
+
+import (
+	"fmt"
+
+	"github.com/zeuxisoo/go-skrip/ast"
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+// SourcePosition maps the instructions from offset to the position of source node
+type SourcePosition struct {
+	Offset   int
+	Position token.Position
+}
+
+// CompiledFunction is the function body which compiled to bytecode, it will be stored in constant pool
+type CompiledFunction struct {
+	Instructions  []byte
+	NumLocals     int
+	LocalNames    []string // for resolving the local or free variable which is not set yet by name
+	FreeNames     []string
+	NumParameters int
+	Positions     []SourcePosition // sorted by offset
+	Constants     []Object         // the constant pool of its program, the function may be called by the later evaluation
+
+	// Keep the source nodes for inspect the function like evaluator
+	Parameters []*ast.IdentifierExpression
+	Block      *ast.BlockStatement
+}
+
+func (c *CompiledFunction) Type() ObjectType {
+	return COMPILED_FUNCTION_OBJECT
+}
+
+func (c *CompiledFunction) Inspect() string {
+	return fmt.Sprintf("compiled function[%p]", c)
+}
+
+// PositionAt returns the source position of instruction at offset
+func (c *CompiledFunction) PositionAt(offset int) token.Position {
+	var position token.Position
+
+	for _, sourcePosition := range c.Positions {
+		if sourcePosition.Offset > offset {
+			break
+		}
+
+		position = sourcePosition.Position
+	}
+
+	return position
+}
@@ -1,7 +1,51 @@
 package object
 
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+// Error kinds
+const (
+	RUNTIME_ERROR = "RuntimeError" // raised by interpreter or built-in function
+	THROW_ERROR   = "ThrowError"   // raised by throw statement
+	EXIT_ERROR    = "ExitError"    // raised by exit function, it cannot be caught
+
+	PERMISSION_ERROR = "PermissionError" // raised when calling the built-in function without granted capability
+
+	CANCEL_ERROR           = "CancelError"          // raised when the context of program is cancelled
+	STEP_LIMIT_ERROR       = "StepLimitError"       // raised when the steps exceeded
+	CALL_DEPTH_ERROR       = "CallDepthError"       // raised when the call depth exceeded
+	ALLOCATION_LIMIT_ERROR = "AllocationLimitError" // raised when the allocations exceeded
+)
+
+// The maximum frames in stack trace like Go, the deep recursion will print too many frames
+const maxStackTraceFrames = 100
+
 type Error struct {
-	Message string
+	Message  string
+	Kind     string
+	Position token.Position // the location of node which raised the error
+	Value    Object         // the thrown value of throw statement or the code of exit function
+	Stack    []StackFrame   // the calling functions when the error raised, start from the innermost call
+}
+
+func NewError(kind string, format string, values ...interface{}) *Error {
+	return &Error{
+		Message: fmt.Sprintf(format, values...),
+		Kind:    kind,
+	}
+}
+
+// NewExitError returns the error which stops the program with the exit code
+func NewExitError(code int64) *Error {
+	return &Error{
+		Message: fmt.Sprintf("exit status %d", code),
+		Kind:    EXIT_ERROR,
+		Value:   &Integer{Value: code},
+	}
 }
 
 func (e *Error) Type() ObjectType {
@@ -11,3 +55,52 @@ func (e *Error) Type() ObjectType {
 func (e *Error) Inspect() string {
 	return "[Error] " + e.Message
 }
+
+// ExitCode returns the code of exit function, it is false when the error is not raised by exit function
+func (e *Error) ExitCode() (int, bool) {
+	if e.Kind != EXIT_ERROR {
+		return 0, false
+	}
+
+	code, ok := e.Value.(*Integer)
+	if ok == false {
+		return 0, false
+	}
+
+	return int(code.Value), true
+}
+
+// StackTrace returns the error message and the calling functions like the panic message of Go, e.g.
+//
+//	RuntimeError: Identifier not found: b
+//
+//	f(...)
+//		main.sk:2:7
+//	main()
+//		main.sk:4:1
+func (e *Error) StackTrace() string {
+	var out bytes.Buffer
+
+	out.WriteString(e.Kind + ": " + e.Message + "\n")
+
+	// The innermost function is running at the error position, the others are running at the call position
+	position := e.Position
+
+	for index, frame := range e.Stack {
+		if index >= maxStackTraceFrames {
+			out.WriteString("\n...additional frames elided...")
+
+			return out.String()
+		}
+
+		out.WriteString("\n" + frame.Function + "(...)\n")
+		out.WriteString("\t" + position.String())
+
+		position = frame.Position
+	}
+
+	out.WriteString("\nmain()\n")
+	out.WriteString("\t" + position.String())
+
+	return out.String()
+}
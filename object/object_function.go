@@ -8,9 +8,16 @@ import (
 )
 
 type Function struct {
+	Name        string // shown in signature instead of func when it is set, e.g. the constructor of struct Point(x, y)
 	Parameters  []*ast.IdentifierExpression
+	Defaults    map[string]ast.Expression // evaluated in the scope of function when the argument is missing
+	Rest        *ast.IdentifierExpression
 	Block       *ast.BlockStatement
 	Environment *Environment
+
+	// Only for the function which created by vm
+	Compiled *CompiledFunction
+	Free     []Object
 }
 
 func (f *Function) Type() ObjectType {
@@ -20,18 +27,22 @@ func (f *Function) Type() ObjectType {
 func (f *Function) Inspect() string {
 	var out bytes.Buffer
 
-	parameters := []string{}
-	for _, parameter := range f.Parameters {
-		parameters = append(parameters, parameter.String())
-	}
-
-	out.WriteString("func")
-	out.WriteString("(")
-	out.WriteString(strings.Join(parameters, ", "))
-	out.WriteString(")")
+	out.WriteString(f.Signature())
 	out.WriteString(" { ")
 	out.WriteString(f.Block.String())
 	out.WriteString(" } ")
 
 	return out.String()
 }
+
+// Signature returns the function with its parameters only, e.g. func(a, b = 2, ...rest)
+func (f *Function) Signature() string {
+	parameters := ast.ParameterStrings(f.Parameters, f.Defaults, f.Rest)
+
+	name := "func"
+	if f.Name != "" {
+		name = f.Name
+	}
+
+	return name + "(" + strings.Join(parameters, ", ") + ")"
+}
@@ -0,0 +1,38 @@
+package object
+
+type Module struct {
+	Name        string
+	Path        string
+	Environment *Environment
+}
+
+func (m *Module) Type() ObjectType {
+	return MODULE_OBJECT
+}
+
+func (m *Module) Inspect() string {
+	return "module " + m.Name
+}
+
+// ModuleCache keeps the loaded modules and the loading chain (for cycle detection) of a program
+type ModuleCache struct {
+	Loaded  map[string]*Module
+	Loading []string
+}
+
+func NewModuleCache() *ModuleCache {
+	return &ModuleCache{
+		Loaded:  make(map[string]*Module),
+		Loading: []string{},
+	}
+}
+
+func (m *ModuleCache) IsLoading(path string) bool {
+	for _, loading := range m.Loading {
+		if loading == path {
+			return true
+		}
+	}
+
+	return false
+}
@@ -16,6 +16,21 @@ func (s *String) Inspect() string {
 	return s.Value
 }
 
+func (s *String) Iterable() bool {
+	return true
+}
+
+// Chars splits the string into characters by code point, e.g. "héllo" is [h, é, l, l, o]
+func (s *String) Chars() []Object {
+	chars := make([]Object, 0, len(s.Value))
+
+	for _, char := range s.Value {
+		chars = append(chars, &String{Value: string(char)})
+	}
+
+	return chars
+}
+
 func (s *String) HashKey() HashKey {
 	h := fnv.New64a()
 	h.Write([]byte(s.Inspect()))
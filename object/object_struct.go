@@ -0,0 +1,59 @@
+package object
+
+import (
+	"strings"
+
+	"github.com/zeuxisoo/go-skrip/ast"
+)
+
+// Struct is the user-defined type which declared by struct statement, it is called like function to create the instance,
+// e.g. Point(1, 2) or Point(y: 2, x: 1)
+type Struct struct {
+	Name        string
+	Fields      []*ast.IdentifierExpression
+	Defaults    map[string]ast.Expression // evaluated in the scope of constructor when the argument is missing
+	Methods     map[string]*Function
+	Environment *Environment
+}
+
+func (s *Struct) Type() ObjectType {
+	return STRUCT_OBJECT
+}
+
+func (s *Struct) Inspect() string {
+	fields := ast.ParameterStrings(s.Fields, s.Defaults, nil)
+	if len(fields) == 0 {
+		return "struct " + s.Name + " {}"
+	}
+
+	return "struct " + s.Name + " { " + strings.Join(fields, ", ") + " }"
+}
+
+// Constructor returns the function which binds the arguments to the fields like the parameters of function
+func (s *Struct) Constructor() *Function {
+	return &Function{
+		Name:        s.Name,
+		Parameters:  s.Fields,
+		Defaults:    s.Defaults,
+		Environment: s.Environment,
+	}
+}
+
+// Instance is the value of struct, the fields are declared by struct only
+type Instance struct {
+	Struct *Struct
+	Fields map[string]Object
+}
+
+func (i *Instance) Type() ObjectType {
+	return INSTANCE_OBJECT
+}
+
+func (i *Instance) Inspect() string {
+	fields := []string{}
+	for _, field := range i.Struct.Fields {
+		fields = append(fields, field.Value+": "+i.Fields[field.Value].Inspect())
+	}
+
+	return i.Struct.Name + "{" + strings.Join(fields, ", ") + "}"
+}
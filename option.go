@@ -0,0 +1,87 @@
+package skrip
+
+import (
+	"io"
+
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// Option configures the interpreter when it created by New
+type Option func(*Interpreter)
+
+// WithEngine sets the execution engine, default is EvaluatorEngine
+func WithEngine(engine Engine) Option {
+	return func(i *Interpreter) {
+		i.engine = engine
+	}
+}
+
+// WithStdout sets the writer of print functions, default is os.Stdout
+func WithStdout(writer io.Writer) Option {
+	return func(i *Interpreter) {
+		i.environment.SetStdout(writer)
+	}
+}
+
+// WithStderr sets the writer of error output, default is os.Stderr
+func WithStderr(writer io.Writer) Option {
+	return func(i *Interpreter) {
+		i.environment.SetStderr(writer)
+	}
+}
+
+// WithBuiltIn registers the built-in function to the interpreter only
+func WithBuiltIn(name string, function object.BuiltInFunction, options ...BuiltInOption) Option {
+	return func(i *Interpreter) {
+		i.RegisterBuiltIn(name, function, options...)
+	}
+}
+
+// WithMethod registers the method of object type to the interpreter only
+func WithMethod(objectType object.ObjectType, name string, function object.BuiltInFunction, options ...BuiltInOption) Option {
+	return func(i *Interpreter) {
+		i.RegisterMethod(objectType, name, function, options...)
+	}
+}
+
+// WithCapabilities grants the capabilities to the interpreter only, e.g. skrip.WithCapabilities(object.IO_CAPABILITY),
+// all capabilities are granted when this option is not used
+func WithCapabilities(capabilities ...object.Capability) Option {
+	return func(i *Interpreter) {
+		if capabilities == nil {
+			capabilities = []object.Capability{}
+		}
+
+		i.environment.SetCapabilities(capabilities)
+	}
+}
+
+// WithLimits sets the execution limits of each evaluation, e.g. the maximum steps, call depth and allocations
+func WithLimits(limits object.Limits) Option {
+	return func(i *Interpreter) {
+		i.limits = limits
+	}
+}
+
+// BuiltInOption configures the built-in function when it registered to the interpreter
+type BuiltInOption func(*object.BuiltIn)
+
+// RequireCapability makes the built-in function callable only when the capability is granted by WithCapabilities,
+// e.g. the function which reads the file should require object.FS_CAPABILITY
+func RequireCapability(capability object.Capability) BuiltInOption {
+	return func(builtIn *object.BuiltIn) {
+		builtIn.Capability = capability
+	}
+}
+
+func newBuiltIn(function object.BuiltInFunction, options []BuiltInOption) *object.BuiltIn {
+	builtIn := &object.BuiltIn{
+		Function: function,
+	}
+
+	for _, option := range options {
+		option(builtIn)
+	}
+
+	return builtIn
+}
@@ -18,8 +18,9 @@ type Parser struct {
 	lexer  *lexer.Lexer
 	errors errorStrings
 
-	currentToken token.Token
-	peekToken    token.Token
+	currentToken  token.Token
+	peekToken     token.Token
+	pendingTokens []token.Token // the tokens after peek token which read by lookahead
 
 	prefixParseFunctions map[token.Type]prefixParseFunction
 	infixParseFunctions  map[token.Type]infixParseFunction
@@ -39,6 +40,8 @@ func NewParser(lexer *lexer.Lexer) *Parser {
 	parser.registerPrefixParseFunction(token.INT, parser.parseIntegerLiteral)
 	parser.registerPrefixParseFunction(token.FLOAT, parser.parseFloatLiteral)
 	parser.registerPrefixParseFunction(token.STRING, parser.parseStringLiteral)
+	parser.registerPrefixParseFunction(token.TEMPLATE_HEAD, parser.parseTemplateLiteral)
+	parser.registerPrefixParseFunction(token.ILLEGAL, parser.parseIllegal)
 	parser.registerPrefixParseFunction(token.NIL, parser.parseNilExpression)
 	parser.registerPrefixParseFunction(token.FUNCTION, parser.parseFunctionLiteral)
 	parser.registerPrefixParseFunction(token.IDENTIFIER, parser.parseIdentifier)
@@ -51,9 +54,11 @@ func NewParser(lexer *lexer.Lexer) *Parser {
 	parser.registerPrefixParseFunction(token.LEFT_BRACE, parser.parseHashLiteral)
 	parser.registerPrefixParseFunction(token.LEFT_PARENTHESIS, parser.parseGroupedExpression)
 	parser.registerPrefixParseFunction(token.IF, parser.parseIfExpression)
+	parser.registerPrefixParseFunction(token.MATCH, parser.parseMatchExpression)
 	parser.registerPrefixParseFunction(token.FOR, parser.parseForExpression)
 	parser.registerPrefixParseFunction(token.BREAK, parser.parseBreakExpression)
 	parser.registerPrefixParseFunction(token.CONTINUE, parser.parseContinueExpression)
+	parser.registerPrefixParseFunction(token.TRY, parser.parseTryExpression)
 
 	parser.infixParseFunctions = make(map[token.Type]infixParseFunction)
 	parser.registerInfixParseFunction(token.PLUS, parser.parseInfixExpression)
@@ -82,6 +87,9 @@ func (p *Parser) Parse() *ast.Program {
 		Statements: []ast.Statement{},
 	}
 
+	start := p.currentToken.Position()
+	end := p.currentToken.End
+
 	for !p.currentTokenTypeIs(token.EOF) {
 		statement := p.parseStatement()
 
@@ -90,9 +98,13 @@ func (p *Parser) Parse() *ast.Program {
 			program.Statements = append(program.Statements, statement)
 		}
 
+		end = p.currentToken.End
+
 		p.nextToken()
 	}
 
+	program.SetSpan(start, end)
+
 	return program
 }
 
@@ -107,6 +119,12 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.STRUCT:
+		return p.parseStructStatement()
 	case token.FUNCTION:
 		// If next token is token.identifier, parse by function statement e.g. "func name() {}"
 		// otherwise, parse by function literal expression e.g. "func() {}"
@@ -127,19 +145,31 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		Token: p.currentToken,
 	}
 
-	// If next token is identifier
-	//		call nextToken() to set the current token point to this
-	// otherwise, the token is not identifier
-	//		return nil
-	if p.expectPeekTokenTypeIs(token.IDENTIFIER) == false {
-		return nil
-	}
+	// If next token is "[" or "{", parse the destructuring pattern like let [a, b] = array
+	if p.peekTokenTypeIs(token.LEFT_BRACKET) == true || p.peekTokenTypeIs(token.LEFT_BRACE) == true {
+		p.nextToken()
 
-	// Set the LetStatement Name point to IdentifierExpression struct
-	// and set the variable Token struct and variable name
-	statement.Name = &ast.IdentifierExpression{
-		Token: p.currentToken,
-		Value: p.currentToken.Literal,
+		statement.Pattern = p.parsePattern()
+		if statement.Pattern == nil {
+			return nil
+		}
+	} else {
+		// If next token is identifier
+		//		call nextToken() to set the current token point to this
+		// otherwise, the token is not identifier
+		//		return nil
+		if p.expectPeekTokenTypeIs(token.IDENTIFIER) == false {
+			return nil
+		}
+
+		// Set the LetStatement Name point to IdentifierExpression struct
+		// and set the variable Token struct and variable name
+		statement.Name = &ast.IdentifierExpression{
+			Token: p.currentToken,
+			Value: p.currentToken.Literal,
+		}
+
+		p.setSpan(statement.Name, p.currentToken.Position())
 	}
 
 	// Ensure that next token is assign symbol, and set the current token point to this
@@ -158,6 +188,8 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		p.nextToken()
 	}
 
+	p.setSpan(statement, statement.Token.Position())
+
 	return statement
 }
 
@@ -172,11 +204,102 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	// Parse the return value expression
 	statement.ReturnValue = p.parseExpression(LOWEST)
 
+	// Return the multiple values as array like return a, b; it can be destructured by let [a, b] = f();
+	if p.peekTokenTypeIs(token.COMMA) == true && statement.ReturnValue != nil {
+		array := &ast.ArrayLiteralExpression{
+			Token:    statement.Token,
+			Elements: []ast.Expression{statement.ReturnValue},
+		}
+
+		start := statement.ReturnValue.StartPos()
+
+		for p.peekTokenTypeIs(token.COMMA) == true {
+			p.nextToken() // set current token to ","
+			p.nextToken() // set current token to next value
+
+			array.Elements = append(array.Elements, p.parseExpression(LOWEST))
+		}
+
+		p.setSpan(array, start)
+
+		statement.ReturnValue = array
+	}
+
+	//
+	if p.peekTokenTypeIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	p.setSpan(statement, statement.Token.Position())
+
+	return statement
+}
+
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	statement := &ast.ImportStatement{
+		Token: p.currentToken,
+	}
+
+	// Move the current token to module path, it can be string or identifier
+	// e.g. import "path/to/lib.sk" or import lib
+	p.nextToken()
+
+	switch p.currentToken.Type {
+	case token.STRING, token.IDENTIFIER:
+		statement.Path = p.currentToken.Literal
+	default:
+		p.errors = append(
+			p.errors,
+			fmt.Sprintf("%s: Expected module path should be string or identifier, but got %s", p.currentToken.Position(), p.currentToken.Type),
+		)
+
+		return nil
+	}
+
+	// If next token is "as", the next identifier will be the module alias name
+	if p.peekTokenTypeIs(token.AS) == true {
+		p.nextToken()
+
+		if p.expectPeekTokenTypeIs(token.IDENTIFIER) == false {
+			return nil
+		}
+
+		statement.Alias = &ast.IdentifierExpression{
+			Token: p.currentToken,
+			Value: p.currentToken.Literal,
+		}
+
+		p.setSpan(statement.Alias, p.currentToken.Position())
+	}
+
+	//
+	if p.peekTokenTypeIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	p.setSpan(statement, statement.Token.Position())
+
+	return statement
+}
+
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	statement := &ast.ThrowStatement{
+		Token: p.currentToken,
+	}
+
+	// Move the current token to thrown value
+	p.nextToken()
+
+	// Parse the thrown value expression
+	statement.Value = p.parseExpression(LOWEST)
+
 	//
 	if p.peekTokenTypeIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
+	p.setSpan(statement, statement.Token.Position())
+
 	return statement
 }
 
@@ -195,14 +318,152 @@ func (p *Parser) parseFunctionStatement() *ast.FunctionStatement {
 		Value: p.currentToken.Literal,
 	}
 
+	p.setSpan(statement.Name, p.currentToken.Position())
+
 	// Parse function literal expression
-	statement.Function = p.parseFunctionLiteral().(*ast.FunctionLiteralExpression)
+	function, ok := p.parseFunctionLiteral().(*ast.FunctionLiteralExpression)
+	if ok == false {
+		return nil
+	}
+
+	statement.Function = function
+
+	p.setSpan(statement.Function, statement.Name.StartPos())
+
+	//
+	if p.peekTokenTypeIs(token.SEMICOLON) == true {
+		p.nextToken()
+	}
+
+	p.setSpan(statement, statement.Token.Position())
+
+	return statement
+}
+
+func (p *Parser) parseStructStatement() *ast.StructStatement {
+	statement := &ast.StructStatement{
+		Token: p.currentToken,
+	}
+
+	// Parse the struct name
+	if p.expectPeekTokenTypeIs(token.IDENTIFIER) == false {
+		return nil
+	}
+
+	statement.Name = &ast.IdentifierExpression{
+		Token: p.currentToken,
+		Value: p.currentToken.Literal,
+	}
+
+	p.setSpan(statement.Name, p.currentToken.Position())
+
+	if p.expectPeekTokenTypeIs(token.LEFT_BRACE) == false {
+		return nil
+	}
+
+	// Move to the first member
+	p.nextToken()
+
+	names := map[string]bool{}
+
+	// Loop until found "}", the member is "field", "field = value" or "func method() { ... }",
+	// they can be separated by "," or ";"
+	for p.currentTokenTypeIs(token.RIGHT_BRACE) == false {
+		var name string
+
+		switch p.currentToken.Type {
+		case token.FUNCTION:
+			if p.peekTokenTypeIs(token.IDENTIFIER) == false {
+				p.errors = append(
+					p.errors,
+					fmt.Sprintf("%s: Expected method name but got %s", p.peekToken.Position(), p.peekToken.Literal),
+				)
+
+				return nil
+			}
+
+			method := p.parseFunctionStatement()
+			if method == nil {
+				return nil
+			}
+
+			name = method.Name.Value
+
+			statement.Methods = append(statement.Methods, method)
+		case token.IDENTIFIER:
+			field := &ast.IdentifierExpression{
+				Token: p.currentToken,
+				Value: p.currentToken.Literal,
+			}
+
+			p.setSpan(field, p.currentToken.Position())
+
+			name = field.Value
+
+			// The fields are the parameters of constructor, so the field without default value can not follow the optional field
+			if p.peekTokenTypeIs(token.ASSIGN) == true {
+				p.nextToken()
+				p.nextToken()
+
+				value := p.parseExpression(LOWEST)
+				if value == nil {
+					return nil
+				}
+
+				if statement.Defaults == nil {
+					statement.Defaults = map[string]ast.Expression{}
+				}
+
+				statement.Defaults[field.Value] = value
+			} else if len(statement.Defaults) > 0 {
+				p.errors = append(
+					p.errors,
+					fmt.Sprintf("%s: Field %s without default value can not follow the field with default value", field.Token.Position(), field.Value),
+				)
+
+				return nil
+			}
+
+			statement.Fields = append(statement.Fields, field)
+		case token.EOF:
+			p.errors = append(p.errors, fmt.Sprintf("%s: Expected } after struct members", p.currentToken.Position()))
+
+			return nil
+		default:
+			p.errors = append(
+				p.errors,
+				fmt.Sprintf("%s: Expected field or method in struct but got %s", p.currentToken.Position(), p.currentToken.Literal),
+			)
+
+			return nil
+		}
+
+		if names[name] == true {
+			p.errors = append(
+				p.errors,
+				fmt.Sprintf("%s: Duplicate member %s in struct %s", p.currentToken.Position(), name, statement.Name.Value),
+			)
+
+			return nil
+		}
+
+		names[name] = true
+
+		if p.peekTokenTypeIs(token.COMMA) == true || p.peekTokenTypeIs(token.SEMICOLON) == true {
+			p.nextToken()
+		}
+
+		// Move to the next member or "}"
+		p.nextToken()
+	}
 
 	//
 	if p.peekTokenTypeIs(token.SEMICOLON) == true {
 		p.nextToken()
 	}
 
+	p.setSpan(statement, statement.Token.Position())
+
 	return statement
 }
 
@@ -216,6 +477,8 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 		p.nextToken()
 	}
 
+	p.setSpan(statement, statement.Token.Position())
+
 	return statement
 }
 
@@ -230,6 +493,9 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 	//  4. fire the prefix parse function from prefixParseFunctions[token.INT]
 	//  5. continue lookup each token when found token.SEMICOLON and ensure precedence less than next token precedence
 
+	// Save the start position of expression, the infix expression will be started from the left expression too
+	start := p.currentToken.Position()
+
 	// Get the prefix parse callback function from registered list like (if, function, !, -, 5, 5.1, "text")
 	prefixParseFunction := p.prefixParseFunctions[p.currentToken.Type]
 
@@ -243,6 +509,8 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 	// If the current token type is registered in prefix parse function list, fire the prefix parse function
 	leftExpression := prefixParseFunction()
 
+	p.setSpan(leftExpression, start)
+
 	// Continue lookup the following tokens
 	// Loop each token
 	// 		unitil found semicolon token
@@ -266,6 +534,8 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 			p.nextToken()
 
 			leftExpression = infixParseFunction(leftExpression)
+
+			p.setSpan(leftExpression, start)
 		}
 	}
 
@@ -317,7 +587,7 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	if err != nil {
 		p.errors = append(
 			p.errors,
-			fmt.Sprintf("Line: %d, Can not parse %q as integer", p.currentToken.LineNumber, p.currentToken.Literal),
+			fmt.Sprintf("%s: Can not parse %q as integer", p.currentToken.Position(), p.currentToken.Literal),
 		)
 
 		return nil
@@ -337,7 +607,7 @@ func (p *Parser) parseFloatLiteral() ast.Expression {
 	if err != nil {
 		p.errors = append(
 			p.errors,
-			fmt.Sprintf("Line: %d, Can not parse %q as float", p.currentToken.LineNumber, p.currentToken.Literal),
+			fmt.Sprintf("%s: Can not parse %q as float", p.currentToken.Position(), p.currentToken.Literal),
 		)
 
 		return nil
@@ -355,6 +625,68 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	}
 }
 
+func (p *Parser) parseTemplateLiteral() ast.Expression {
+	template := &ast.TemplateLiteralExpression{
+		Token: p.currentToken,
+		Parts: []ast.Expression{},
+	}
+
+	// e.g. "a ${b} c ${d}"
+	// -----^^^^^ head
+	// ----------^ expression
+	// -----------^^^^^^ middle
+	// -----------------^ expression
+	// ------------------^^ tail
+	for {
+		if p.currentToken.Literal != "" {
+			text := p.parseStringLiteral()
+
+			p.setSpan(text, p.currentToken.Position())
+
+			template.Parts = append(template.Parts, text)
+		}
+
+		if p.currentTokenTypeIs(token.TEMPLATE_TAIL) {
+			return template
+		}
+
+		p.nextToken()
+
+		if p.currentTokenTypeIs(token.TEMPLATE_MIDDLE) || p.currentTokenTypeIs(token.TEMPLATE_TAIL) {
+			p.errors = append(p.errors, fmt.Sprintf("%s: Expected expression in interpolation", p.currentToken.Position()))
+
+			return nil
+		}
+
+		expression := p.parseExpression(LOWEST)
+		if expression == nil {
+			return nil
+		}
+
+		template.Parts = append(template.Parts, expression)
+
+		if p.peekTokenTypeIs(token.TEMPLATE_MIDDLE) == false && p.peekTokenTypeIs(token.TEMPLATE_TAIL) == false {
+			p.errors = append(
+				p.errors,
+				fmt.Sprintf("%s: Expected } after interpolation but got %s", p.peekToken.Position(), p.peekToken.Literal),
+			)
+
+			return nil
+		}
+
+		p.nextToken()
+	}
+}
+
+func (p *Parser) parseIllegal() ast.Expression {
+	p.errors = append(
+		p.errors,
+		fmt.Sprintf("%s: Illegal token %s", p.currentToken.Position(), p.currentToken.Literal),
+	)
+
+	return nil
+}
+
 func (p *Parser) parseNilExpression() ast.Expression {
 	return &ast.NilLiteralExpression{
 		Token: p.currentToken,
@@ -371,7 +703,9 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	functionLiteralExpression.Parameters = p.parseFunctionParameters()
+	if p.parseFunctionParameters(functionLiteralExpression) == false {
+		return nil
+	}
 
 	// Expect next token is "{"
 	if p.expectPeekTokenTypeIs(token.LEFT_BRACE) == false {
@@ -540,14 +874,26 @@ func (p *Parser) parseIfExpression() ast.Expression {
 }
 
 func (p *Parser) parseForExpression() ast.Expression {
-	// When found "{", mean "for { ... }"
-	if p.peekTokenTypeIs(token.LEFT_BRACE) == true {
+	// When found "{", mean "for { ... }" unless the braces are followed by "in"
+	if p.peekTokenTypeIs(token.LEFT_BRACE) == true && p.peekHashPattern() == false {
 		return p.parseForEverExpression(p.currentToken)
 	}
 
 	// Save current token (token.FOR) for forEachHash and forEachArray
 	tokenFor := p.currentToken
 
+	// When found "[" or "{", mean "for [key, value] in array { ... }" or "for {name, age} in array { ... }"
+	if p.peekTokenTypeIs(token.LEFT_BRACKET) == true || p.peekTokenTypeIs(token.LEFT_BRACE) == true {
+		p.nextToken()
+
+		pattern := p.parsePattern()
+		if pattern == nil {
+			return nil
+		}
+
+		return p.parseForEachArrayOrRangeExpression(tokenFor, pattern)
+	}
+
 	// If next token is not identifier, stop it and return nil
 	// otherwise set current token to this
 	if p.expectPeekTokenTypeIs(token.IDENTIFIER) == false {
@@ -560,7 +906,74 @@ func (p *Parser) parseForExpression() ast.Expression {
 		return p.parseForEachHashExpression(tokenFor, p.currentToken)
 	}
 
-	return p.parseForEachArrayOrRangeExpression(tokenFor, p.currentToken)
+	return p.parseForEachArrayOrRangeExpression(tokenFor, nil)
+}
+
+func (p *Parser) parseTryExpression() ast.Expression {
+	tryExpression := &ast.TryExpression{
+		Token: p.currentToken,
+	}
+
+	// If next token is "{", set current token to this,
+	// otherwise, return nil
+	if p.expectPeekTokenTypeIs(token.LEFT_BRACE) == false {
+		return nil
+	}
+
+	tryExpression.Block = p.parseBlockStatement()
+
+	// When found "catch", the error name is optional, e.g. "catch (e) { ... }" or "catch { ... }"
+	if p.peekTokenTypeIs(token.CATCH) == true {
+		p.nextToken()
+
+		if p.peekTokenTypeIs(token.LEFT_PARENTHESIS) == true {
+			p.nextToken()
+
+			if p.expectPeekTokenTypeIs(token.IDENTIFIER) == false {
+				return nil
+			}
+
+			tryExpression.Catch = &ast.IdentifierExpression{
+				Token: p.currentToken,
+				Value: p.currentToken.Literal,
+			}
+
+			p.setSpan(tryExpression.Catch, p.currentToken.Position())
+
+			if p.expectPeekTokenTypeIs(token.RIGHT_PARENTHESIS) == false {
+				return nil
+			}
+		}
+
+		if p.expectPeekTokenTypeIs(token.LEFT_BRACE) == false {
+			return nil
+		}
+
+		tryExpression.CatchBlock = p.parseBlockStatement()
+	}
+
+	// When found "finally", it will be run after try or catch block
+	if p.peekTokenTypeIs(token.FINALLY) == true {
+		p.nextToken()
+
+		if p.expectPeekTokenTypeIs(token.LEFT_BRACE) == false {
+			return nil
+		}
+
+		tryExpression.Finally = p.parseBlockStatement()
+	}
+
+	// The try block must be followed by catch or finally block
+	if tryExpression.CatchBlock == nil && tryExpression.Finally == nil {
+		p.errors = append(
+			p.errors,
+			fmt.Sprintf("%s: Expected catch or finally block after try block, but got %s", p.peekToken.Position(), p.peekToken.Type),
+		)
+
+		return nil
+	}
+
+	return tryExpression
 }
 
 func (p *Parser) parseBreakExpression() ast.Expression {
@@ -660,7 +1073,7 @@ func (p *Parser) parseCallExpression(leftExpression ast.Expression) ast.Expressi
 		Function: leftExpression,
 	}
 
-	call.Arguments = p.parseExpressionList(token.RIGHT_PARENTHESIS)
+	call.Arguments = p.parseCallArguments()
 
 	return call
 }
@@ -675,7 +1088,7 @@ func (p *Parser) parseAssignExpression(leftExpression ast.Expression) ast.Expres
 	default:
 		p.errors = append(
 			p.errors,
-			fmt.Sprintf("Line: %d, Expected identifier or index expression on left but got %s", p.currentToken.LineNumber, p.currentToken.Literal),
+			fmt.Sprintf("%s: Expected identifier or index expression on left but got %s", p.currentToken.Position(), p.currentToken.Literal),
 		)
 
 		return nil
@@ -694,6 +1107,8 @@ func (p *Parser) parseAssignExpression(leftExpression ast.Expression) ast.Expres
 }
 
 func (p *Parser) parseDotExpression(leftExpression ast.Expression) ast.Expression {
+	dotToken := p.currentToken
+
 	// Ensure the token is identifier after dot symbol
 	// self.identifier = item
 	// -----^^^^^^^^^^
@@ -706,9 +1121,12 @@ func (p *Parser) parseDotExpression(leftExpression ast.Expression) ast.Expressio
 		Value: p.currentToken.Literal,
 	}
 
+	p.setSpan(item, p.currentToken.Position())
+
 	dot := &ast.DotExpression{
-		Left: leftExpression,
-		Item: item,
+		Token: dotToken,
+		Left:  leftExpression,
+		Item:  item,
 	}
 
 	return dot
@@ -717,7 +1135,26 @@ func (p *Parser) parseDotExpression(leftExpression ast.Expression) ast.Expressio
 // Helper functions
 func (p *Parser) nextToken() {
 	p.currentToken = p.peekToken
-	p.peekToken = p.lexer.NextToken()
+
+	if len(p.pendingTokens) > 0 {
+		p.peekToken = p.pendingTokens[0]
+		p.pendingTokens = p.pendingTokens[1:]
+	} else {
+		p.peekToken = p.lexer.NextToken()
+	}
+}
+
+// lookahead returns the token after peek token by offset, the offset 0 is the peek token
+func (p *Parser) lookahead(offset int) token.Token {
+	if offset == 0 {
+		return p.peekToken
+	}
+
+	for len(p.pendingTokens) < offset {
+		p.pendingTokens = append(p.pendingTokens, p.lexer.NextToken())
+	}
+
+	return p.pendingTokens[offset-1]
 }
 
 func (p *Parser) expectPeekTokenTypeIs(tokenType token.Type) bool {
@@ -755,7 +1192,20 @@ func (p *Parser) currentPrecedence() int {
 	return LOWEST
 }
 
-func (p *Parser) registerPrefixParseFunction(tokenType token.Type, callback prefixParseFunction) {
+// Set the span of node from start position to the end of current token
+func (p *Parser) setSpan(node ast.Node, start token.Position) {
+	if node == nil {
+		return
+	}
+
+	if spanNode, ok := node.(interface {
+		SetSpan(start token.Position, end token.Position)
+	}); ok {
+		spanNode.SetSpan(start, p.currentToken.End)
+	}
+}
+
+func (p *Parser) registerPrefixParseFunction(tokenType token.Type, callback prefixParseFunction) {
 	p.prefixParseFunctions[tokenType] = callback
 }
 
@@ -764,39 +1214,521 @@ func (p *Parser) registerInfixParseFunction(tokenType token.Type, callback infix
 }
 
 // Helper function for parse prefix function like function arguments, function block and so on
-func (p *Parser) parseFunctionParameters() []*ast.IdentifierExpression {
-	identifierExpressions := []*ast.IdentifierExpression{}
+func (p *Parser) parseFunctionParameters(function *ast.FunctionLiteralExpression) bool {
+	function.Parameters = []*ast.IdentifierExpression{}
 
 	// If the next token is ")", it means no arguments
 	// so, move to next token and return empty arguments
 	if p.peekTokenTypeIs(token.RIGHT_PARENTHESIS) {
 		p.nextToken()
 
-		return identifierExpressions
+		return true
 	}
 
-	// Current in "(", so move it to next token
-	p.nextToken()
+	names := map[string]bool{}
 
-	// Loop until found ")"
+	// Loop until found ")", the parameter may be "a", "a = 1" or "...a"
 	for p.currentTokenTypeIs(token.RIGHT_PARENTHESIS) == false {
+		// Current in "(" or ",", so move it to next token
+		p.nextToken()
+
+		variadic := p.currentTokenTypeIs(token.ELLIPSIS)
+		if variadic == true {
+			p.nextToken()
+		}
+
+		if p.currentTokenTypeIs(token.IDENTIFIER) == false {
+			p.errors = append(
+				p.errors,
+				fmt.Sprintf("%s: Expected parameter name but got %s", p.currentToken.Position(), p.currentToken.Literal),
+			)
+
+			return false
+		}
+
 		// Append the parameter identifier to parameter identifiers
 		identifierExpression := &ast.IdentifierExpression{
 			Token: p.currentToken,
 			Value: p.currentToken.Literal,
 		}
-		identifierExpressions = append(identifierExpressions, identifierExpression)
 
-		// Move to next token
+		p.setSpan(identifierExpression, p.currentToken.Position())
+
+		if names[identifierExpression.Value] == true {
+			p.errors = append(
+				p.errors,
+				fmt.Sprintf("%s: Duplicate parameter %s", p.currentToken.Position(), identifierExpression.Value),
+			)
+
+			return false
+		}
+
+		names[identifierExpression.Value] = true
+
+		switch {
+		// The variadic parameter collects the remaining arguments, so it must be the last one
+		case variadic == true:
+			if p.peekTokenTypeIs(token.RIGHT_PARENTHESIS) == false {
+				p.errors = append(
+					p.errors,
+					fmt.Sprintf("%s: Variadic parameter %s must be the last parameter", p.currentToken.Position(), identifierExpression.Value),
+				)
+
+				return false
+			}
+
+			function.Rest = identifierExpression
+		// The default value is evaluated when calling, so keep the expression only
+		case p.peekTokenTypeIs(token.ASSIGN) == true:
+			p.nextToken()
+			p.nextToken()
+
+			value := p.parseExpression(LOWEST)
+			if value == nil {
+				return false
+			}
+
+			if function.Defaults == nil {
+				function.Defaults = map[string]ast.Expression{}
+			}
+
+			function.Defaults[identifierExpression.Value] = value
+			function.Parameters = append(function.Parameters, identifierExpression)
+		// The required parameter can not follow the optional parameter, otherwise the positional arguments are ambiguous
+		default:
+			if len(function.Defaults) > 0 {
+				p.errors = append(
+					p.errors,
+					fmt.Sprintf("%s: Parameter %s without default value can not follow the parameter with default value", p.currentToken.Position(), identifierExpression.Value),
+				)
+
+				return false
+			}
+
+			function.Parameters = append(function.Parameters, identifierExpression)
+		}
+
+		// Move to next "," or ")"
+		if p.peekTokenTypeIs(token.COMMA) == true {
+			p.nextToken()
+		} else if p.expectPeekTokenTypeIs(token.RIGHT_PARENTHESIS) == false {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *Parser) parseMatchExpression() ast.Expression {
+	matchExpression := &ast.MatchExpression{
+		Token: p.currentToken,
+	}
+
+	// Move to the subject and parse it
+	p.nextToken()
+
+	matchExpression.Subject = p.parseExpression(LOWEST)
+	if matchExpression.Subject == nil {
+		return nil
+	}
+
+	if p.expectPeekTokenTypeIs(token.LEFT_BRACE) == false {
+		return nil
+	}
+
+	// Move to the pattern of first arm
+	p.nextToken()
+
+	// Loop until found "}", each arm is "pattern => expression" or "pattern if guard => { block }"
+	for p.currentTokenTypeIs(token.RIGHT_BRACE) == false {
+		if p.currentTokenTypeIs(token.EOF) == true {
+			p.errors = append(p.errors, fmt.Sprintf("%s: Expected } after match arms", p.currentToken.Position()))
+
+			return nil
+		}
+
+		arm := &ast.MatchArm{}
+
+		arm.Pattern = p.parseMatchPattern()
+		if arm.Pattern == nil {
+			return nil
+		}
+
+		if p.peekTokenTypeIs(token.IF) == true {
+			p.nextToken() // set current token to "if"
+			p.nextToken() // set current token to guard
+
+			arm.Guard = p.parseExpression(LOWEST)
+			if arm.Guard == nil {
+				return nil
+			}
+		}
+
+		if p.expectPeekTokenTypeIs(token.ARROW) == false {
+			return nil
+		}
+
+		// The block is started by "{", otherwise wrap the expression into block,
+		// so the hash literal should be grouped like 1 => ({"a": 1})
+		if p.peekTokenTypeIs(token.LEFT_BRACE) == true {
+			p.nextToken()
+
+			arm.Block = p.parseBlockStatement()
+		} else {
+			p.nextToken()
+
+			start := p.currentToken.Position()
+
+			statement := &ast.ExpressionStatement{
+				Token:      p.currentToken,
+				Expression: p.parseExpression(LOWEST),
+			}
+
+			if statement.Expression == nil {
+				return nil
+			}
+
+			p.setSpan(statement, start)
+
+			arm.Block = &ast.BlockStatement{
+				Token:      statement.Token,
+				Statements: []ast.Statement{statement},
+			}
+
+			p.setSpan(arm.Block, start)
+		}
+
+		matchExpression.Arms = append(matchExpression.Arms, arm)
+
+		// The arms are separated by "," or "}" of block
+		if p.peekTokenTypeIs(token.COMMA) == true {
+			p.nextToken()
+		}
+
+		// Move to the pattern of next arm or "}"
+		p.nextToken()
+	}
+
+	return matchExpression
+}
+
+// Helper function for parse the pattern of match arm, it may be the alternatives like 1 | 2
+func (p *Parser) parseMatchPattern() ast.Expression {
+	pattern := p.parseMatchPatternItem()
+	if pattern == nil || p.peekTokenTypeIs(token.PIPE) == false {
+		return pattern
+	}
+
+	orPattern := &ast.OrPattern{
+		Token:    p.peekToken,
+		Patterns: []ast.Expression{pattern},
+	}
+
+	for p.peekTokenTypeIs(token.PIPE) == true {
+		p.nextToken() // set current token to "|"
+		p.nextToken() // set current token to next pattern
+
+		pattern := p.parseMatchPatternItem()
+		if pattern == nil {
+			return nil
+		}
+
+		orPattern.Patterns = append(orPattern.Patterns, pattern)
+	}
+
+	p.setSpan(orPattern, orPattern.Patterns[0].StartPos())
+
+	return orPattern
+}
+
+// The pattern of match can be the binding name, "_", array, hash, literal or range of literals
+func (p *Parser) parseMatchPatternItem() ast.Expression {
+	switch p.currentToken.Type {
+	case token.IDENTIFIER:
+		return p.parsePattern()
+	case token.LEFT_BRACKET:
+		return p.parseArrayPattern(p.parseMatchPattern)
+	case token.LEFT_BRACE:
+		return p.parseHashPattern(p.parseMatchPattern)
+	}
+
+	start := p.currentToken
+	numErrors := len(p.errors)
+
+	// The incomplete expression like "!" or "1 +" is reported already, its parts may be nil
+	expression := p.parseExpression(LOWEST)
+	if expression == nil || len(p.errors) > numErrors {
+		return nil
+	}
+
+	if isLiteralPattern(expression) == false {
+		p.errors = append(
+			p.errors,
+			fmt.Sprintf("%s: Expected literal, range, array, hash or binding pattern but got %s", start.Position(), expression.String()),
+		)
+
+		return nil
+	}
+
+	return expression
+}
+
+// Helper function for parse destructuring pattern like [a, b, ...rest] or {name, age: years, ...rest},
+// the element of pattern can be the nested pattern
+func (p *Parser) parsePattern() ast.Expression {
+	switch p.currentToken.Type {
+	case token.IDENTIFIER:
+		identifierExpression := &ast.IdentifierExpression{
+			Token: p.currentToken,
+			Value: p.currentToken.Literal,
+		}
+
+		p.setSpan(identifierExpression, p.currentToken.Position())
+
+		return identifierExpression
+	case token.LEFT_BRACKET:
+		return p.parseArrayPattern(p.parsePattern)
+	case token.LEFT_BRACE:
+		return p.parseHashPattern(p.parsePattern)
+	default:
+		p.errors = append(
+			p.errors,
+			fmt.Sprintf("%s: Expected identifier or destructuring pattern but got %s", p.currentToken.Position(), p.currentToken.Literal),
+		)
+
+		return nil
+	}
+}
+
+// The element is parsed by the given function, so the pattern of let and match can share it
+func (p *Parser) parseArrayPattern(parseElement func() ast.Expression) ast.Expression {
+	pattern := &ast.ArrayPattern{
+		Token: p.currentToken,
+	}
+
+	start := p.currentToken.Position()
+
+	// Loop until found "]", the element may be "a", "[a, b]", "{a}" or "...rest"
+	for p.currentTokenTypeIs(token.RIGHT_BRACKET) == false {
+		// Current in "[" or ",", so move it to next token, and stop when the pattern is empty like []
+		if p.peekTokenTypeIs(token.RIGHT_BRACKET) == true && len(pattern.Elements) == 0 {
+			p.nextToken()
+
+			break
+		}
+
+		p.nextToken()
+
+		if p.currentTokenTypeIs(token.ELLIPSIS) == true {
+			pattern.Rest = p.parsePatternRest(token.RIGHT_BRACKET)
+			if pattern.Rest == nil {
+				return nil
+			}
+
+			break
+		}
+
+		element := parseElement()
+		if element == nil {
+			return nil
+		}
+
+		pattern.Elements = append(pattern.Elements, element)
+
+		// Move to next "," or "]"
+		if p.peekTokenTypeIs(token.COMMA) == true {
+			p.nextToken()
+		} else if p.expectPeekTokenTypeIs(token.RIGHT_BRACKET) == false {
+			return nil
+		}
+	}
+
+	p.setSpan(pattern, start)
+
+	return pattern
+}
+
+func (p *Parser) parseHashPattern(parseElement func() ast.Expression) ast.Expression {
+	pattern := &ast.HashPattern{
+		Token: p.currentToken,
+	}
+
+	start := p.currentToken.Position()
+
+	// Loop until found "}", the pair may be "name", "name: pattern" or "...rest"
+	for p.currentTokenTypeIs(token.RIGHT_BRACE) == false {
+		// Current in "{" or ",", so move it to next token, and stop when the pattern is empty like {}
+		if p.peekTokenTypeIs(token.RIGHT_BRACE) == true && len(pattern.Pairs) == 0 {
+			p.nextToken()
+
+			break
+		}
+
+		p.nextToken()
+
+		if p.currentTokenTypeIs(token.ELLIPSIS) == true {
+			pattern.Rest = p.parsePatternRest(token.RIGHT_BRACE)
+			if pattern.Rest == nil {
+				return nil
+			}
+
+			break
+		}
+
+		if p.currentTokenTypeIs(token.IDENTIFIER) == false {
+			p.errors = append(
+				p.errors,
+				fmt.Sprintf("%s: Expected key name in hash pattern but got %s", p.currentToken.Position(), p.currentToken.Literal),
+			)
+
+			return nil
+		}
+
+		key := &ast.IdentifierExpression{
+			Token: p.currentToken,
+			Value: p.currentToken.Literal,
+		}
+
+		p.setSpan(key, p.currentToken.Position())
+
+		pair := &ast.HashPatternPair{
+			Key:   key,
+			Value: key,
+		}
+
+		// The value of key is taken into other name or nested pattern like age: years
+		if p.peekTokenTypeIs(token.COLON) == true {
+			p.nextToken() // set current token to ":"
+			p.nextToken() // set current token to pattern
+
+			pair.Value = parseElement()
+			if pair.Value == nil {
+				return nil
+			}
+		}
+
+		pattern.Pairs = append(pattern.Pairs, pair)
+
+		// Move to next "," or "}"
+		if p.peekTokenTypeIs(token.COMMA) == true {
+			p.nextToken()
+		} else if p.expectPeekTokenTypeIs(token.RIGHT_BRACE) == false {
+			return nil
+		}
+	}
+
+	p.setSpan(pattern, start)
+
+	return pattern
+}
+
+// The rest name must be the last one of pattern, so the end of pattern is expected after it,
+// the name can be omitted like [a, ...], it will be named "_" which will not be bound
+func (p *Parser) parsePatternRest(endTokenType token.Type) *ast.IdentifierExpression {
+	rest := &ast.IdentifierExpression{
+		Token: p.currentToken,
+		Value: "_",
+	}
+
+	if p.peekTokenTypeIs(endTokenType) == false {
+		if p.expectPeekTokenTypeIs(token.IDENTIFIER) == false {
+			return nil
+		}
+
+		rest.Token = p.currentToken
+		rest.Value = p.currentToken.Literal
+	}
+
+	p.setSpan(rest, p.currentToken.Position())
+
+	if p.peekTokenTypeIs(endTokenType) == false {
+		p.errors = append(
+			p.errors,
+			fmt.Sprintf("%s: Rest element %s must be the last element of pattern", p.currentToken.Position(), rest.Value),
+		)
+
+		return nil
+	}
+
+	p.nextToken()
+
+	return rest
+}
+
+// Helper function for parse call arguments, the named arguments like "b: 3" must be placed after the positional arguments
+func (p *Parser) parseCallArguments() []ast.Expression {
+	arguments := []ast.Expression{}
+
+	// If next token is ")", it means no arguments
+	if p.peekTokenTypeIs(token.RIGHT_PARENTHESIS) {
 		p.nextToken()
 
-		// If current token is ",", skip it then move to next token
-		if p.currentTokenTypeIs(token.COMMA) == true {
+		return arguments
+	}
+
+	names := map[string]bool{}
+
+	for p.currentTokenTypeIs(token.RIGHT_PARENTHESIS) == false {
+		// Current in "(" or ",", so move it to next argument
+		p.nextToken()
+
+		if p.currentTokenTypeIs(token.IDENTIFIER) == true && p.peekTokenTypeIs(token.COLON) == true {
+			namedArgument := &ast.NamedArgumentExpression{
+				Token: p.currentToken,
+				Name: &ast.IdentifierExpression{
+					Token: p.currentToken,
+					Value: p.currentToken.Literal,
+				},
+			}
+
+			start := p.currentToken.Position()
+
+			p.setSpan(namedArgument.Name, start)
+
+			if names[namedArgument.Name.Value] == true {
+				p.errors = append(
+					p.errors,
+					fmt.Sprintf("%s: Duplicate named argument %s", start, namedArgument.Name.Value),
+				)
+
+				return nil
+			}
+
+			names[namedArgument.Name.Value] = true
+
+			p.nextToken() // set current token to ":"
+			p.nextToken() // set current token to value
+
+			namedArgument.Value = p.parseExpression(LOWEST)
+			if namedArgument.Value == nil {
+				return nil
+			}
+
+			p.setSpan(namedArgument, start)
+
+			arguments = append(arguments, namedArgument)
+		} else {
+			if len(names) > 0 {
+				p.errors = append(
+					p.errors,
+					fmt.Sprintf("%s: Positional argument can not follow the named argument", p.currentToken.Position()),
+				)
+
+				return nil
+			}
+
+			arguments = append(arguments, p.parseExpression(LOWEST))
+		}
+
+		// Move to next "," or ")"
+		if p.peekTokenTypeIs(token.COMMA) == true {
 			p.nextToken()
+		} else if p.expectPeekTokenTypeIs(token.RIGHT_PARENTHESIS) == false {
+			return nil
 		}
 	}
 
-	return identifierExpressions
+	return arguments
 }
 
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
@@ -820,6 +1752,8 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 		p.nextToken()
 	}
 
+	p.setSpan(blockStatement, blockStatement.Token.Position())
+
 	return blockStatement
 }
 
@@ -838,6 +1772,27 @@ func (p *Parser) parseForEverExpression(currentToken token.Token) ast.Expression
 	return forEverExpression
 }
 
+// The hash pattern of for loop like "for {name} in people" starts with "{" like the block of "for { ... }",
+// so the tokens are read ahead until the closing brace, it is the hash pattern when followed by "in"
+func (p *Parser) peekHashPattern() bool {
+	depth := 0
+
+	for offset := 0; ; offset++ {
+		switch p.lookahead(offset).Type {
+		case token.LEFT_BRACE:
+			depth++
+		case token.RIGHT_BRACE:
+			depth--
+
+			if depth == 0 {
+				return p.lookahead(offset+1).Type == token.IN
+			}
+		case token.EOF:
+			return false
+		}
+	}
+}
+
 func (p *Parser) parseForEachHashExpression(tokenFor token.Token, currentToken token.Token) ast.Expression {
 	forEachHashExpression := &ast.ForEachHashExpression{
 		Token: tokenFor,
@@ -882,10 +1837,16 @@ func (p *Parser) parseForEachHashExpression(tokenFor token.Token, currentToken t
 	return forEachHashExpression
 }
 
-func (p *Parser) parseForEachArrayOrRangeExpression(tokenFor token.Token, currentToken token.Token) ast.Expression {
+func (p *Parser) parseForEachArrayOrRangeExpression(tokenFor token.Token, pattern ast.Expression) ast.Expression {
 	forEachArrayOrRangeExpression := &ast.ForEachArrayOrRangeExpression{
 		Token: tokenFor,
-		Value: p.currentToken.Literal,
+	}
+
+	// The current token is the value name or the end of pattern
+	if pattern != nil {
+		forEachArrayOrRangeExpression.Pattern = pattern
+	} else {
+		forEachArrayOrRangeExpression.Value = p.currentToken.Literal
 	}
 
 	// If next token is "in", set current token to it
@@ -911,13 +1872,34 @@ func (p *Parser) parseForEachArrayOrRangeExpression(tokenFor token.Token, curren
 	return forEachArrayOrRangeExpression
 }
 
+// The literal pattern is compared with the subject of match, the range pattern checks the subject is in range
+func isLiteralPattern(expression ast.Expression) bool {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteralExpression, *ast.FloatLiteralExpression, *ast.StringLiteralExpression:
+		return true
+	case *ast.BooleanExpression, *ast.NilLiteralExpression:
+		return true
+	case *ast.PrefixExpression:
+		switch expression.Right.(type) {
+		case *ast.IntegerLiteralExpression, *ast.FloatLiteralExpression:
+			return expression.Operator == "-"
+		}
+	case *ast.RangeExpression:
+		_, isRange := expression.Start.(*ast.RangeExpression)
+
+		return isRange == false && isLiteralPattern(expression.Start) == true && isLiteralPattern(expression.End) == true
+	}
+
+	return false
+}
+
 // Error handle functions
 func (p *Parser) peekTokenTypeError(tokenType token.Type) {
-	message := fmt.Sprintf("Line: %d, Expected peek token type should be %s, but got %s", p.currentToken.LineNumber, tokenType, p.peekToken.Type)
+	message := fmt.Sprintf("%s: Expected peek token type should be %s, but got %s", p.peekToken.Position(), tokenType, p.peekToken.Type)
 	p.errors = append(p.errors, message)
 }
 
 func (p *Parser) noPrefixParseFunctionError(tokenType token.Type) {
-	message := fmt.Sprintf("Line: %d, Can not found related prefix parse function for %s", p.currentToken.LineNumber, tokenType)
+	message := fmt.Sprintf("%s: Can not found related prefix parse function for %s", p.currentToken.Position(), tokenType)
 	p.errors = append(p.errors, message)
 }
@@ -36,6 +36,40 @@ func TestLetStatement(t *testing.T) {
 	})
 }
 
+func TestDestructuringPattern(t *testing.T) {
+	Convey("Destructuring pattern test", t, func() {
+		expecteds := []struct {
+			source   string
+			expected string
+		}{
+			{"let [a, b] = c;", "let [a, b] = c;"},
+			{"let [a, [b, c], ...rest] = d", "let [a, [b, c], ...rest] = d;"},
+			{"let [] = a", "let [] = a;"},
+			{"let {name, age: years} = person", "let {name, age: years} = person;"},
+			{"let {user: {name}, tags: [first], ...rest} = a", "let {user: {name}, tags: [first], ...rest} = a;"},
+			{"for [k, v] in pairs { k }", "for [k, v] in pairs { k } "},
+			{"for {name, age: years} in people { name }", "for {name, age: years} in people { name } "},
+			{"for {user: {name}} in list { let a = {\"b\": {}}; a }", "for {user: {name}} in list { let a = {b:{}};a } "},
+			{"for { break; }", "for { break; } "},
+			{"for { let a = {}; break; }", "for { let a = {};break; } "},
+			{"return a, b + 1;", "return [a, (b + 1)];"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				theLexer := lexer.NewLexer(expected.source)
+				theParser := NewParser(theLexer)
+				theProgram := theParser.Parse()
+
+				testParserError(theParser)
+				testParserProgramLength(theProgram, 1)
+
+				So(theProgram.String(), ShouldEqual, expected.expected)
+			})
+		}
+	})
+}
+
 func TestBadLetStatement(t *testing.T) {
 	Convey("Bad let statement testing", t, func() {
 		sources := []string{"let", "let x;"}
@@ -107,6 +141,104 @@ func TestFunctionStatement(t *testing.T) {
 	})
 }
 
+func TestImportStatement(t *testing.T) {
+	Convey("Import statement testing", t, func() {
+		expecteds := []struct {
+			source string
+			path   string
+			alias  string
+		}{
+			{`import "path/to/lib.sk";`, "path/to/lib.sk", ""},
+			{`import "path/to/lib.sk" as l;`, "path/to/lib.sk", "l"},
+			{`import lib`, "lib", ""},
+			{`import lib as l`, "lib", "l"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				theLexer := lexer.NewLexer(expected.source)
+				theParser := NewParser(theLexer)
+				theProgram := theParser.Parse()
+
+				Convey("Parse program check", func() {
+					testParserError(theParser)
+					testParserProgramLength(theProgram, 1)
+				})
+
+				importStatement, ok := theProgram.Statements[0].(*ast.ImportStatement)
+				Convey("Can convert to import statement", func() {
+					So(ok, ShouldBeTrue)
+				})
+
+				Convey(runMessage("Import path should be equals %s", expected.path), func() {
+					So(importStatement.Path, ShouldEqual, expected.path)
+				})
+
+				Convey(runMessage("Import alias should be equals %s", expected.alias), func() {
+					if expected.alias == "" {
+						So(importStatement.Alias, ShouldBeNil)
+					} else {
+						So(importStatement.Alias.Value, ShouldEqual, expected.alias)
+					}
+				})
+			})
+		}
+	})
+}
+
+func TestBadImportStatement(t *testing.T) {
+	Convey("Bad import statement testing", t, func() {
+		sources := []string{"import", "import 5", `import "lib" as`}
+
+		for _, source := range sources {
+			theLexer := lexer.NewLexer(source)
+			theParser := NewParser(theLexer)
+			theParser.Parse()
+
+			So(len(theParser.Errors()), ShouldBeGreaterThanOrEqualTo, 1)
+		}
+	})
+}
+
+func TestThrowStatement(t *testing.T) {
+	Convey("Throw statement testing", t, func() {
+		expecteds := []struct {
+			source string
+			value  string
+		}{
+			{`throw "foo";`, "foo"},
+			{`throw 5`, "5"},
+			{`throw a + b;`, "(a + b)"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				theLexer := lexer.NewLexer(expected.source)
+				theParser := NewParser(theLexer)
+				theProgram := theParser.Parse()
+
+				Convey("Parse program check", func() {
+					testParserError(theParser)
+					testParserProgramLength(theProgram, 1)
+				})
+
+				throwStatement, ok := theProgram.Statements[0].(*ast.ThrowStatement)
+				Convey("Can convert to throw statement", func() {
+					So(ok, ShouldBeTrue)
+				})
+
+				Convey("Throw statement token literal should be throw", func() {
+					So(throwStatement.TokenLiteral(), ShouldEqual, "throw")
+				})
+
+				Convey(runMessage("Throw value should be equals %s", expected.value), func() {
+					So(throwStatement.Value.String(), ShouldEqual, expected.value)
+				})
+			})
+		}
+	})
+}
+
 func TestIntegerLiteralExpression(t *testing.T) {
 	Convey("Integer literal expression test", t, func() {
 		source := `5;`
@@ -197,6 +329,45 @@ func TestStringLiteralExpression(t *testing.T) {
 	})
 }
 
+func TestTemplateLiteralExpression(t *testing.T) {
+	Convey("Template literal expression test", t, func() {
+		expecteds := []struct {
+			source string
+			parts  []string
+			result string
+		}{
+			{`"hello ${name}";`, []string{"hello ", "name"}, `"hello ${name}"`},
+			{`"${a + 1} and ${b.c}!";`, []string{"(a + 1)", " and ", "b.c", "!"}, `"${(a + 1)} and ${b.c}!"`},
+			{`"${"x${y}"}";`, []string{`"x${y}"`}, `"${"x${y}"}"`},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				theLexer := lexer.NewLexer(expected.source)
+				theParser := NewParser(theLexer)
+				theProgram := theParser.Parse()
+
+				testParserError(theParser)
+				testParserProgramLength(theProgram, 1)
+
+				statement, ok := theProgram.Statements[0].(*ast.ExpressionStatement)
+				So(ok, ShouldBeTrue)
+
+				template, ok := statement.Expression.(*ast.TemplateLiteralExpression)
+				So(ok, ShouldBeTrue)
+				So(template.String(), ShouldEqual, expected.result)
+
+				parts := []string{}
+				for _, part := range template.Parts {
+					parts = append(parts, part.String())
+				}
+
+				So(parts, ShouldResemble, expected.parts)
+			})
+		}
+	})
+}
+
 func TestNilLiteralExpression(t *testing.T) {
 	Convey("Nil expression test", t, func() {
 		source := `nil;`
@@ -438,6 +609,33 @@ func TestNoNamedFunctionParameterParsing(t *testing.T) {
 	})
 }
 
+func TestFunctionDefaultAndVariadicParameterParsing(t *testing.T) {
+	Convey("Function default and variadic parameter parsing test", t, func() {
+		expecteds := []struct {
+			source   string
+			expected string
+		}{
+			{"func(a, b = 2) {};", "func(a, b = 2) {  }"},
+			{"func(a = 1 + 2, b = a) {};", "func(a = (1 + 2), b = a) {  }"},
+			{"func(...rest) {};", "func(...rest) {  }"},
+			{"func f(a, b = [1], ...rest) {};", "func f(a, b = [1], ...rest) {  }"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				theLexer := lexer.NewLexer(expected.source)
+				theParser := NewParser(theLexer)
+				theProgram := theParser.Parse()
+
+				testParserError(theParser)
+				testParserProgramLength(theProgram, 1)
+
+				So(theProgram.String(), ShouldEqual, expected.expected)
+			})
+		}
+	})
+}
+
 func TestIdentifierExpression(t *testing.T) {
 	Convey("Identifier expression test", t, func() {
 		source := `foobar;`
@@ -1143,6 +1341,8 @@ func TestCallExpression(t *testing.T) {
 			{"add(1, 2 * 3, 4 + 5)", "add(1, (2 * 3), (4 + 5))"},
 			{"a + add(b * c) + d", "((a + add((b * c))) + d)"},
 			{"add(a * b[4], b[5], 2 * [6, 7][1])", "add((a * (b[4])), (b[5]), (2 * ([6, 7][1])))"},
+			{"add(1, b: 2 * 3, c: d)", "add(1, b: (2 * 3), c: d)"},
+			{"add(f(1), g(a: h(2)), 3)", "add(f(1), g(a: h(2)), 3)"},
 		}
 
 		for index, expression := range expectedExpressions {
@@ -1636,6 +1836,136 @@ func TestContinueExpression(t *testing.T) {
 	})
 }
 
+func TestTryExpression(t *testing.T) {
+	Convey("Try expression test", t, func() {
+		expecteds := []struct {
+			source     string
+			catch      string
+			hasCatch   bool
+			hasFinally bool
+			result     string
+		}{
+			{`try { a } catch (e) { b }`, "e", true, false, "try { a } catch (e) { b }"},
+			{`try { a } catch { b }`, "", true, false, "try { a } catch { b }"},
+			{`try { a } finally { c }`, "", false, true, "try { a } finally { c }"},
+			{`try { a } catch (e) { b } finally { c }`, "e", true, true, "try { a } catch (e) { b } finally { c }"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				theLexer := lexer.NewLexer(expected.source)
+				theParser := NewParser(theLexer)
+				theProgram := theParser.Parse()
+
+				Convey("Parse program check", func() {
+					testParserError(theParser)
+					testParserProgramLength(theProgram, 1)
+				})
+
+				statement, ok := theProgram.Statements[0].(*ast.ExpressionStatement)
+				Convey("Can convert to expression statement", func() {
+					So(ok, ShouldBeTrue)
+				})
+
+				tryExpression, ok := statement.Expression.(*ast.TryExpression)
+				Convey("Can convert to try expression", func() {
+					So(ok, ShouldBeTrue)
+				})
+
+				Convey(runMessage("Catch error name should be equals %s", expected.catch), func() {
+					if expected.catch == "" {
+						So(tryExpression.Catch, ShouldBeNil)
+					} else {
+						testIdentifierExpression(tryExpression.Catch, expected.catch)
+					}
+				})
+
+				Convey("Catch and finally block check", func() {
+					So(tryExpression.CatchBlock != nil, ShouldEqual, expected.hasCatch)
+					So(tryExpression.Finally != nil, ShouldEqual, expected.hasFinally)
+				})
+
+				Convey(runMessage("Try expression string should be equals %s", expected.result), func() {
+					So(tryExpression.String(), ShouldEqual, expected.result)
+				})
+			})
+		}
+	})
+}
+
+func TestMatchExpression(t *testing.T) {
+	Convey("Match expression test", t, func() {
+		expecteds := []struct {
+			source   string
+			expected string
+		}{
+			{`match a { 1 | 2 => "small", _ => "big" }`, "match a { 1 | 2 => { small }, _ => { big } }"},
+			{`match a { -1 => b, 0..10 => c }`, "match a { (-1) => { b }, (0..10) => { c } }"},
+			{`match a { [first, ...] => first, [x, ...rest] => rest }`, "match a { [first, ...] => { first }, [x, ...rest] => { rest } }"},
+			{`match a { {type: "x", data} => data }`, "match a { {type: x, data} => { data } }"},
+			{`match a { n if n > 10 => { n; } }`, "match a { n if (n > 10) => { n } }"},
+			{`match a { [1 | 2, {b: nil}] => true }`, "match a { [1 | 2, {b: nil}] => { true } }"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				theLexer := lexer.NewLexer(expected.source)
+				theParser := NewParser(theLexer)
+				theProgram := theParser.Parse()
+
+				testParserError(theParser)
+				testParserProgramLength(theProgram, 1)
+
+				So(theProgram.String(), ShouldEqual, expected.expected)
+			})
+		}
+	})
+}
+
+func TestStructStatement(t *testing.T) {
+	Convey("Struct statement test", t, func() {
+		expecteds := []struct {
+			source   string
+			expected string
+		}{
+			{`struct Point { x, y }`, "struct Point { x, y }"},
+			{`struct Point { x; y = 0; }`, "struct Point { x, y = 0 }"},
+			{`struct Point { x, func sum(n = 1) { self.x + n } }`, "struct Point { x, func sum(n = 1) { (self.x + n) } }"},
+			{`struct Empty {}`, "struct Empty {}"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+				theLexer := lexer.NewLexer(expected.source)
+				theParser := NewParser(theLexer)
+				theProgram := theParser.Parse()
+
+				testParserError(theParser)
+				testParserProgramLength(theProgram, 1)
+
+				_, ok := theProgram.Statements[0].(*ast.StructStatement)
+
+				So(ok, ShouldBeTrue)
+				So(theProgram.String(), ShouldEqual, expected.expected)
+			})
+		}
+	})
+}
+
+func TestBadTryExpression(t *testing.T) {
+	Convey("Bad try expression testing", t, func() {
+		sources := []string{"try { a }", "try a", "try { a } catch (5) { b }", "try { a } catch (e { b }"}
+
+		for _, source := range sources {
+			theLexer := lexer.NewLexer(source)
+			theParser := NewParser(theLexer)
+			theParser.Parse()
+
+			So(len(theParser.Errors()), ShouldBeGreaterThanOrEqualTo, 1)
+		}
+	})
+}
+
 func TestAssignExpression(t *testing.T) {
 	Convey("Assign expression test", t, func() {
 		expectedExpressions := []struct {
@@ -1687,6 +2017,7 @@ func TestDotExpression(t *testing.T) {
 			value  interface{}
 		}{
 			{`let hash = {}; hash.foo = 123;`, "hash", "foo", "123"},
+			{`let hash = {}; hash.foo.bar = 123;`, "hash.foo", "bar", "123"},
 		}
 
 		for index, expression := range expectedExpressions {
@@ -1734,6 +2065,120 @@ func TestDotExpression(t *testing.T) {
 	})
 }
 
+func TestNodeSpan(t *testing.T) {
+	Convey("Node span test", t, func() {
+		source := "let a = 1 + foo(2, b);\nfunc f(x) {\n  x.y[0]\n}\n(1 + 2) * 3"
+
+		theLexer := lexer.NewLexer(source)
+		theParser := NewParser(theLexer)
+		theProgram := theParser.Parse()
+
+		Convey("Parse program check", func() {
+			testParserError(theParser)
+			testParserProgramLength(theProgram, 3)
+		})
+
+		letStatement := theProgram.Statements[0].(*ast.LetStatement)
+		infixExpression := letStatement.Value.(*ast.InfixExpression)
+		callExpression := infixExpression.Right.(*ast.CallExpression)
+
+		functionStatement := theProgram.Statements[1].(*ast.FunctionStatement)
+		blockStatement := functionStatement.Function.Block
+		indexExpression := blockStatement.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IndexExpression)
+
+		expecteds := []struct {
+			node   ast.Node
+			source string
+		}{
+			{theProgram, source},
+			{letStatement, "let a = 1 + foo(2, b);"},
+			{letStatement.Name, "a"},
+			{infixExpression, "1 + foo(2, b)"},
+			{callExpression, "foo(2, b)"},
+			{callExpression.Arguments[1], "b"},
+			{functionStatement, "func f(x) {\n  x.y[0]\n}"},
+			{functionStatement.Function.Parameters[0], "x"},
+			{blockStatement, "{\n  x.y[0]\n}"},
+			{indexExpression, "x.y[0]"},
+			{indexExpression.Left, "x.y"},
+			{indexExpression.Left.(*ast.DotExpression).Item, "y"},
+			{theProgram.Statements[2], "(1 + 2) * 3"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %q", index, expected.source), func() {
+				start := expected.node.StartPos()
+				end := expected.node.EndPos()
+
+				So(source[start.Offset:end.Offset], ShouldEqual, expected.source)
+			})
+		}
+
+		Convey("Line and column check", func() {
+			So(indexExpression.StartPos().Line, ShouldEqual, 3)
+			So(indexExpression.StartPos().Column, ShouldEqual, 3)
+			So(indexExpression.EndPos().Line, ShouldEqual, 3)
+			So(indexExpression.EndPos().Column, ShouldEqual, 9)
+		})
+	})
+}
+
+func TestParserErrorPosition(t *testing.T) {
+	Convey("Parser error position test", t, func() {
+		expecteds := []struct {
+			source string
+			file   string
+			result string
+		}{
+			{"let a = 1;\nlet = 2", "main.sk", "main.sk:2:5: Expected peek token type should be IDENTIFIER, but got ="},
+			{"let a = 1;\nlet = 2", "", "2:5: Expected peek token type should be IDENTIFIER, but got ="},
+			{"  import 5", "lib.sk", "lib.sk:1:10: Expected module path should be string or identifier, but got INT"},
+			{`let a = "foo\qbar";`, "", `1:9: Illegal token \q`},
+			{`let a = "foo`, "", `1:9: Illegal token "foo`},
+			{`let a = "${}";`, "", "1:12: Expected expression in interpolation"},
+			{`let a = "${b c}";`, "", "1:14: Expected } after interpolation but got c"},
+			{"func(a, 1) {}", "", "1:9: Expected parameter name but got 1"},
+			{"func(a, a) {}", "", "1:9: Duplicate parameter a"},
+			{"func(...a, b) {}", "", "1:9: Variadic parameter a must be the last parameter"},
+			{"func(a = 1, b) {}", "", "1:13: Parameter b without default value can not follow the parameter with default value"},
+			{"func(a b) {}", "", "1:8: Expected peek token type should be ), but got IDENTIFIER"},
+			{"f(a: 1, 2)", "", "1:9: Positional argument can not follow the named argument"},
+			{"f(a: 1, a: 2)", "", "1:9: Duplicate named argument a"},
+			{"let [a, 1] = b", "", "1:9: Expected identifier or destructuring pattern but got 1"},
+			{"let [...a, b] = c", "", "1:9: Rest element a must be the last element of pattern"},
+			{"let {1} = b", "", "1:6: Expected key name in hash pattern but got 1"},
+			{"let {a b} = c", "", "1:8: Expected peek token type should be }, but got IDENTIFIER"},
+			{"match a { 1 + b => c }", "", "1:11: Expected literal, range, array, hash or binding pattern but got (1 + b)"},
+			{"match a { b(1) => c }", "", "1:12: Expected peek token type should be =>, but got ("},
+			{"match a { 1 c }", "", "1:13: Expected peek token type should be =>, but got IDENTIFIER"},
+			{"match a { 1 => c", "", "1:17: Expected } after match arms"},
+			{"match a { ! }", "", "1:13: Can not found related prefix parse function for }"},
+			{"match a { 1 + }", "", "1:15: Can not found related prefix parse function for }"},
+			{"match a { 1 | - => c }", "", "1:17: Can not found related prefix parse function for =>"},
+			{"match a { [!] => c }", "", "1:13: Can not found related prefix parse function for ]"},
+			{"struct { x }", "", "1:8: Expected peek token type should be IDENTIFIER, but got {"},
+			{"struct P { x = 1, y }", "", "1:19: Field y without default value can not follow the field with default value"},
+			{"struct P { x, x }", "", "1:15: Duplicate member x in struct P"},
+			{"struct P { 1 }", "", "1:12: Expected field or method in struct but got 1"},
+			{"struct P { func() {} }", "", "1:16: Expected method name but got ("},
+			{"struct P { x", "", "1:13: Expected } after struct members"},
+		}
+
+		for index, expected := range expecteds {
+			Convey(runMessage("Running: %d, Source: %q", index, expected.source), func() {
+				theLexer := lexer.NewLexer(expected.source)
+				theLexer.SetFile(expected.file)
+
+				theParser := NewParser(theLexer)
+				theParser.Parse()
+
+				So(len(theParser.Errors()), ShouldBeGreaterThanOrEqualTo, 1)
+				So(theParser.Errors()[0], ShouldEqual, expected.result)
+			})
+		}
+	})
+}
+
 // Sub method for test case
 func testLetStatement(expectedStatements []expectedLetStatement) {
 	for index, currentStatement := range expectedStatements {
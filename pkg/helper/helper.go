@@ -1,7 +1,13 @@
 package helper
 
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// IsLetter reports whether the character can be used in identifier, e.g. a, _, é, 名
 func IsLetter(chr rune) bool {
-	return chr >= 'a' && chr <= 'z' || chr >= 'A' && chr <= 'Z' || chr == '_'
+	return chr >= 'a' && chr <= 'z' || chr >= 'A' && chr <= 'Z' || chr == '_' || chr >= utf8.RuneSelf && unicode.IsLetter(chr)
 }
 
 func IsDigit(chr rune) bool {
@@ -11,3 +17,7 @@ func IsDigit(chr rune) bool {
 func IsDot(chr rune) bool {
 	return chr == '.'
 }
+
+func IsHexDigit(chr rune) bool {
+	return IsDigit(chr) || chr >= 'a' && chr <= 'f' || chr >= 'A' && chr <= 'F'
+}
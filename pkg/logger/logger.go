@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 
@@ -57,6 +58,13 @@ func Write(level LEVEL, format string, values ...interface{}) {
 	logger.Print(outputColors[level](message))
 }
 
+// WriteTo is the output method for other writer, e.g. the stderr of interpreter
+func WriteTo(writer io.Writer, level LEVEL, format string, values ...interface{}) {
+	message := FormatMessage(level, format, values...)
+
+	fmt.Fprintln(writer, outputColors[level](message))
+}
+
 // Trace for trace log
 func Trace(format string, values ...interface{}) {
 	Write(TRACE, format, values...)
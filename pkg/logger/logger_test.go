@@ -59,6 +59,13 @@ func TestLogger(t *testing.T) {
 			So(loggerCapturer.Result(), ShouldEqual, FormatMessage(FATAL, "Hello %s", "fatal"))
 		})
 
+		Convey("Write to method", func() {
+			buffer := &bytes.Buffer{}
+
+			WriteTo(buffer, ERROR, "Hello %s", "writer")
+			So(buffer.String(), ShouldEqual, FormatMessage(ERROR, "Hello %s", "writer")+"\n")
+		})
+
 		Convey("Basic method", func() {
 			Trace("Hello %s", "trace")
 			So(loggerCapturer.Result(), ShouldEqual, FormatMessage(TRACE, "Hello %s", "trace"))
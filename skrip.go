@@ -1,25 +1,193 @@
-package main
+package skrip
 
 import (
-	"os"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sync"
 
-	"github.com/urfave/cli"
+	"github.com/zeuxisoo/go-skrip/ast"
+	"github.com/zeuxisoo/go-skrip/evaluator"
+	"github.com/zeuxisoo/go-skrip/lexer"
+	"github.com/zeuxisoo/go-skrip/object"
+	"github.com/zeuxisoo/go-skrip/parser"
+	"github.com/zeuxisoo/go-skrip/pkg/logger"
+	"github.com/zeuxisoo/go-skrip/vm"
+)
+
+// Engine is the name of execution engine
+type Engine string
 
-	"github.com/zeuxisoo/go-skrip/cmd"
+const (
+	EvaluatorEngine Engine = "evaluator" // tree walking evaluator
+	VMEngine        Engine = "vm"        // bytecode virtual machine, the program which cannot be compiled yet is run by evaluator
 )
 
-const AppVersion = "0.1.0"
+// Interpreter runs the scripts in the same global environment, so the variables can be shared between each evaluation.
+// It is safe for concurrent use, the evaluations of an interpreter are run one by one,
+// use multiple interpreters to run the scripts in parallel
+type Interpreter struct {
+	mutex       sync.Mutex
+	environment *object.Environment
+	engine      Engine
+	limits      object.Limits
+}
+
+// New creates the interpreter with options, e.g. skrip.New(skrip.WithEngine(skrip.VMEngine))
+func New(options ...Option) *Interpreter {
+	interpreter := &Interpreter{
+		environment: object.NewEnvironment(),
+		engine:      EvaluatorEngine,
+	}
+
+	for _, option := range options {
+		option(interpreter)
+	}
+
+	return interpreter
+}
+
+// Eval runs the source code and returns the value of last statement
+func (i *Interpreter) Eval(source string) (object.Object, error) {
+	return i.EvalContext(context.Background(), source)
+}
+
+// EvalContext runs the source code until it finished or the context cancelled
+func (i *Interpreter) EvalContext(ctx context.Context, source string) (object.Object, error) {
+	return i.eval(ctx, source, "")
+}
+
+// EvalFile runs the script file, the import path in the script is relative to this file
+func (i *Interpreter) EvalFile(path string) (object.Object, error) {
+	return i.EvalFileContext(context.Background(), path)
+}
 
-func main() {
-	app := cli.NewApp()
-	app.Name = "Skrip"
-	app.Usage = "This is a skrip language usage"
-	app.Version = AppVersion
-	app.Commands = []cli.Command{
-		cmd.Run,
-		cmd.Eval,
-		cmd.Cli,
+// EvalFileContext runs the script file until it finished or the context cancelled
+func (i *Interpreter) EvalFileContext(ctx context.Context, path string) (object.Object, error) {
+	contentBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
 
-	app.Run(os.Args)
+	return i.eval(ctx, string(contentBytes), path)
+}
+
+// Set defines the global variable for scripts
+func (i *Interpreter) Set(name string, value object.Object) {
+	i.environment.Set(name, value)
+}
+
+// Get returns the global variable which defined by Set or scripts
+func (i *Interpreter) Get(name string) (object.Object, bool) {
+	return i.environment.Get(name)
+}
+
+// RegisterBuiltIn registers the built-in function to this interpreter only,
+// e.g. interpreter.RegisterBuiltIn("read", read, skrip.RequireCapability(object.FS_CAPABILITY))
+func (i *Interpreter) RegisterBuiltIn(name string, function object.BuiltInFunction, options ...BuiltInOption) {
+	i.environment.SetBuiltIn(name, newBuiltIn(function, options))
+}
+
+// RegisterMethod registers the method of object type to this interpreter only, e.g. "abc".title(),
+// the receiver is passed as the first argument
+func (i *Interpreter) RegisterMethod(objectType object.ObjectType, name string, function object.BuiltInFunction, options ...BuiltInOption) {
+	i.environment.SetMethod(objectType, name, newBuiltIn(function, options))
+}
+
+// RegisterFunc registers the Go function as built-in function to this interpreter only,
+// the arguments and result will be converted automatically, e.g. func(a string, b int) (string, error)
+func (i *Interpreter) RegisterFunc(name string, function interface{}, options ...BuiltInOption) error {
+	builtIn, err := object.NewBuiltInFunc(name, function)
+	if err != nil {
+		return err
+	}
+
+	i.environment.SetBuiltIn(name, newBuiltIn(builtIn.Function, options))
+
+	return nil
+}
+
+// PrintError writes the parse errors or the runtime error with its call stack to the stderr writer,
+// nothing will be written for the error which raised by exit function
+func (i *Interpreter) PrintError(err error) {
+	stderr := i.environment.Stderr()
+
+	switch err := err.(type) {
+	case nil:
+		return
+	case *ParseError:
+		for _, message := range err.Messages {
+			logger.WriteTo(stderr, logger.ERROR, "%s", message)
+		}
+	case *RuntimeError:
+		if _, ok := err.ExitCode(); ok == true {
+			return
+		}
+
+		logger.WriteTo(stderr, logger.ERROR, "%s", err.StackTrace())
+	default:
+		logger.WriteTo(stderr, logger.ERROR, "%v", err)
+	}
+}
+
+func (i *Interpreter) eval(ctx context.Context, source string, file string) (result object.Object, err error) {
+	run, err := i.findEngine()
+	if err != nil {
+		return nil, err
+	}
+
+	theLexer := lexer.NewLexer(source)
+	theLexer.SetFile(file)
+
+	theParser := parser.NewParser(theLexer)
+	theProgram := theParser.Parse()
+
+	if len(theParser.Errors()) > 0 {
+		return nil, &ParseError{
+			Messages: theParser.Errors(),
+		}
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	// The import path is resolved by the file of environment, restore it for next evaluation
+	previousFile := i.environment.File()
+	i.environment.SetFile(file)
+	defer i.environment.SetFile(previousFile)
+
+	// Each evaluation has its own context and limits
+	i.environment.SetContext(ctx)
+	i.environment.SetLimits(i.limits)
+	defer i.environment.SetContext(nil)
+
+	// The unexpected panic of engine should not crash the host program
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result, err = nil, &RuntimeError{
+				Err: object.NewError(object.RUNTIME_ERROR, "panic: %v", recovered),
+			}
+		}
+	}()
+
+	result = run(theProgram, i.environment)
+
+	if err, ok := result.(*object.Error); ok {
+		return nil, &RuntimeError{
+			Err: err,
+		}
+	}
+
+	return result, nil
+}
+
+func (i *Interpreter) findEngine() (func(node ast.Node, env *object.Environment) object.Object, error) {
+	switch i.engine {
+	case EvaluatorEngine:
+		return evaluator.Eval, nil
+	case VMEngine:
+		return vm.Eval, nil
+	default:
+		return nil, fmt.Errorf("Unknown engine: %s", i.engine)
+	}
 }
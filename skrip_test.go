@@ -0,0 +1,525 @@
+package skrip_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/zeuxisoo/go-skrip"
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+var engines = []skrip.Engine{
+	skrip.EvaluatorEngine,
+	skrip.VMEngine,
+}
+
+func TestInterpreterEval(t *testing.T) {
+	Convey("Interpreter eval test", t, func() {
+		for _, engine := range engines {
+			Convey(runMessage("Engine: %s", engine), func() {
+				interpreter := skrip.New(skrip.WithEngine(engine))
+
+				Convey("Eval returns the value of last statement", func() {
+					result, err := interpreter.Eval(`let a = 1; a + 2`)
+
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "3")
+				})
+
+				Convey("Global variables are shared between evaluations", func() {
+					_, err := interpreter.Eval(`let a = 10; func add(b) { a + b }`)
+					So(err, ShouldBeNil)
+
+					result, err := interpreter.Eval(`add(5)`)
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "15")
+				})
+
+				Convey("Functions keep their constants after later evaluations", func() {
+					_, err := interpreter.Eval(`func greet(name) { "hello " + name }`)
+					So(err, ShouldBeNil)
+
+					_, err = interpreter.Eval(`let other = "other"; let names = ["a", "b"]`)
+					So(err, ShouldBeNil)
+
+					result, err := interpreter.Eval(`names.map(greet)`)
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "[hello a, hello b]")
+				})
+
+				Convey("Functions and structs defined by evaluator are callable in later evaluations", func() {
+					_, err := interpreter.Eval(`func inc(n, step = 1) { n + step }`)
+					So(err, ShouldBeNil)
+
+					_, err = interpreter.Eval(`struct Point { x, y }`)
+					So(err, ShouldBeNil)
+
+					_, err = interpreter.Eval(`func safe(n) { n }; try { 1 } catch (e) { 2 }`)
+					So(err, ShouldBeNil)
+
+					result, err := interpreter.Eval(`inc(1) + inc(1, 2) + Point(3, 4).y + safe(5)`)
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "14")
+
+					result, err = interpreter.Eval(`[1, 2].map(inc)`)
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "[2, 3]")
+				})
+
+				Convey("Many evaluations do not fill up the constants", func() {
+					for index := 0; index < 70000; index++ {
+						interpreter.Eval(`let x = "y"`)
+					}
+
+					result, err := interpreter.Eval(`"z"`)
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "z")
+				})
+
+				Convey("Set and get global variables", func() {
+					interpreter.Set("name", &object.String{Value: "skrip"})
+
+					_, err := interpreter.Eval(`let greeting = "hello " + name`)
+					So(err, ShouldBeNil)
+
+					greeting, ok := interpreter.Get("greeting")
+					So(ok, ShouldBeTrue)
+					So(greeting.Inspect(), ShouldEqual, "hello skrip")
+
+					_, ok = interpreter.Get("nothing")
+					So(ok, ShouldBeFalse)
+				})
+
+				Convey("Parse error", func() {
+					result, err := interpreter.Eval(`let = 1`)
+
+					_, ok := err.(*skrip.ParseError)
+
+					So(result, ShouldBeNil)
+					So(ok, ShouldBeTrue)
+					So(err.Error(), ShouldContainSubstring, "Expected peek token type should be IDENTIFIER")
+				})
+
+				Convey("Runtime error", func() {
+					result, err := interpreter.Eval(`1 + "a"`)
+
+					runtimeError, ok := err.(*skrip.RuntimeError)
+
+					So(result, ShouldBeNil)
+					So(ok, ShouldBeTrue)
+					So(err.Error(), ShouldEqual, "RuntimeError: Type mismatch INTEGER_OBJECT + STRING_OBJECT")
+					So(runtimeError.Err.Position.String(), ShouldEqual, "1:1")
+				})
+
+				Convey("Crashing script returns runtime error", func() {
+					_, err := interpreter.Eval(`1 / 0`)
+					So(err.Error(), ShouldEqual, "RuntimeError: Division by zero: 1 / 0")
+
+					_, err = interpreter.Eval(`let a = [1]; a[5] = 1`)
+					So(err.Error(), ShouldEqual, "RuntimeError: Array index out of range, Got: 5, Expected: 0 to 0")
+				})
+
+				Convey("Panic is returned as runtime error", func() {
+					interpreter.Set("broken", (*object.String)(nil))
+
+					_, err := interpreter.Eval(`broken + "a"`)
+
+					_, ok := err.(*skrip.RuntimeError)
+
+					So(ok, ShouldBeTrue)
+					So(err.Error(), ShouldStartWith, "RuntimeError: panic: ")
+				})
+
+				Convey("Exit error", func() {
+					_, err := interpreter.Eval(`exit(3)`)
+
+					code, ok := err.(*skrip.RuntimeError).ExitCode()
+
+					So(ok, ShouldBeTrue)
+					So(code, ShouldEqual, 3)
+				})
+			})
+		}
+	})
+}
+
+func TestInterpreterEvalFile(t *testing.T) {
+	Convey("Interpreter eval file test", t, func() {
+		directory, err := ioutil.TempDir("", "skrip")
+		So(err, ShouldBeNil)
+
+		defer os.RemoveAll(directory)
+
+		ioutil.WriteFile(filepath.Join(directory, "lib.sk"), []byte(`func add(a, b) { a + b }`), 0644)
+		ioutil.WriteFile(filepath.Join(directory, "main.sk"), []byte("import \"lib.sk\";\nlet total = lib.add(1, 2);\nfoo"), 0644)
+
+		for _, engine := range engines {
+			Convey(runMessage("Engine: %s", engine), func() {
+				interpreter := skrip.New(skrip.WithEngine(engine))
+
+				_, err := interpreter.EvalFile(filepath.Join(directory, "main.sk"))
+
+				Convey("Import path is relative to the file", func() {
+					total, ok := interpreter.Get("total")
+
+					So(ok, ShouldBeTrue)
+					So(total.Inspect(), ShouldEqual, "3")
+				})
+
+				Convey("Error is located in the file", func() {
+					So(err.(*skrip.RuntimeError).Err.Position.String(), ShouldEqual, filepath.Join(directory, "main.sk")+":3:1")
+				})
+
+				Convey("Imported functions are callable in later evaluations", func() {
+					result, err := interpreter.Eval(`lib.add(total, 4)`)
+
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "7")
+				})
+
+				Convey("File not found", func() {
+					_, err := interpreter.EvalFile(filepath.Join(directory, "nothing.sk"))
+
+					So(os.IsNotExist(err), ShouldBeTrue)
+				})
+			})
+		}
+	})
+}
+
+func TestInterpreterOption(t *testing.T) {
+	Convey("Interpreter option test", t, func() {
+		for _, engine := range engines {
+			Convey(runMessage("Engine: %s", engine), func() {
+				Convey("Stdout writer", func() {
+					stdout := &bytes.Buffer{}
+
+					interpreter := skrip.New(skrip.WithEngine(engine), skrip.WithStdout(stdout))
+					interpreter.Eval(`print("foo"); println("bar", 1)`)
+
+					So(stdout.String(), ShouldEqual, "foobar 1\n")
+				})
+
+				Convey("Built-in function is registered to the interpreter only", func() {
+					double := func(env *object.Environment, arguments ...object.Object) object.Object {
+						return &object.Integer{Value: arguments[0].(*object.Integer).Value * 2}
+					}
+
+					interpreter := skrip.New(skrip.WithEngine(engine), skrip.WithBuiltIn("double", double))
+
+					result, err := interpreter.Eval(`double(21)`)
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "42")
+
+					_, err = skrip.New(skrip.WithEngine(engine)).Eval(`double(21)`)
+					So(err.Error(), ShouldEqual, "RuntimeError: Identifier not found: double")
+				})
+
+				Convey("Go function is registered as built-in function", func() {
+					interpreter := skrip.New(skrip.WithEngine(engine))
+
+					err := interpreter.RegisterFunc("greet", func(name string, times int) (string, error) {
+						if times < 1 {
+							return "", fmt.Errorf("times must be positive, Got: %d", times)
+						}
+
+						return strings.Repeat("hello "+name+" ", times), nil
+					})
+					So(err, ShouldBeNil)
+
+					result, err := interpreter.Eval(`greet("foo", 2)`)
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "hello foo hello foo ")
+
+					_, err = interpreter.Eval(`try { greet("foo", 0) } catch (e) { throw e.message + "!" }`)
+					So(err.Error(), ShouldEqual, "ThrowError: times must be positive, Got: 0!")
+
+					_, err = interpreter.Eval(`greet(1, 2)`)
+					So(err.Error(), ShouldEqual, "RuntimeError: invalid argument 1 for greet: cannot convert INTEGER_OBJECT to string")
+
+					So(interpreter.RegisterFunc("bad", 1).Error(), ShouldEqual, "bad must be a function, Got: int")
+				})
+
+				Convey("Method is registered to the interpreter only", func() {
+					title := func(env *object.Environment, arguments ...object.Object) object.Object {
+						return &object.String{Value: strings.Title(arguments[0].(*object.String).Value)}
+					}
+
+					interpreter := skrip.New(skrip.WithEngine(engine), skrip.WithMethod(object.STRING_OBJECT, "title", title))
+
+					result, err := interpreter.Eval(`"foo bar".title()`)
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "Foo Bar")
+
+					_, err = skrip.New(skrip.WithEngine(engine)).Eval(`"foo bar".title()`)
+					So(err.Error(), ShouldEqual, "RuntimeError: no method title on STRING_OBJECT")
+				})
+
+				Convey("Built-in function can write to stderr writer", func() {
+					stderr := &bytes.Buffer{}
+
+					interpreter := skrip.New(skrip.WithEngine(engine), skrip.WithStderr(stderr))
+					interpreter.RegisterBuiltIn("warn", func(env *object.Environment, arguments ...object.Object) object.Object {
+						fmt.Fprint(env.Stderr(), arguments[0].Inspect())
+
+						return &object.Nil{}
+					})
+
+					interpreter.Eval(`warn("oops")`)
+
+					So(stderr.String(), ShouldEqual, "oops")
+				})
+
+				Convey("Print the error to stderr writer", func() {
+					stderr := &bytes.Buffer{}
+
+					interpreter := skrip.New(skrip.WithEngine(engine), skrip.WithStderr(stderr))
+
+					_, err := interpreter.Eval("func f() {\n  1 + \"a\"\n}\nf()")
+					interpreter.PrintError(err)
+
+					So(stderr.String(), ShouldEqual, "[ERROR] RuntimeError: Type mismatch INTEGER_OBJECT + STRING_OBJECT\n\nf(...)\n\t2:3\nmain()\n\t4:1\n")
+
+					stderr.Reset()
+
+					_, err = interpreter.Eval(`let = 1`)
+					interpreter.PrintError(err)
+
+					So(stderr.String(), ShouldStartWith, "[ERROR] 1:5: Expected peek token type should be IDENTIFIER")
+
+					stderr.Reset()
+
+					_, err = interpreter.Eval(`exit(1)`)
+					interpreter.PrintError(err)
+					interpreter.PrintError(nil)
+
+					So(stderr.String(), ShouldEqual, "")
+				})
+
+				Convey("Built-in function requires the granted capability", func() {
+					stdout := &bytes.Buffer{}
+
+					interpreter := skrip.New(
+						skrip.WithEngine(engine),
+						skrip.WithStdout(stdout),
+						skrip.WithCapabilities(object.IO_CAPABILITY),
+					)
+
+					_, err := interpreter.Eval(`println("foo")`)
+					So(err, ShouldBeNil)
+					So(stdout.String(), ShouldEqual, "foo\n")
+
+					_, err = interpreter.Eval(`env("HOME")`)
+					So(err.Error(), ShouldEqual, "PermissionError: Permission denied, the env capability is not granted")
+
+					result, err := interpreter.Eval(`try { setenv("FOO", "bar") } catch (e) { e.message }`)
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "Permission denied, the env capability is not granted")
+
+					_, err = skrip.New(skrip.WithEngine(engine), skrip.WithCapabilities()).Eval(`print("foo")`)
+					So(err.Error(), ShouldEqual, "PermissionError: Permission denied, the io capability is not granted")
+				})
+
+				Convey("Registered built-in function requires the capability", func() {
+					read := func(env *object.Environment, arguments ...object.Object) object.Object {
+						return &object.String{Value: "content"}
+					}
+
+					interpreter := skrip.New(
+						skrip.WithEngine(engine),
+						skrip.WithCapabilities(object.IO_CAPABILITY),
+						skrip.WithBuiltIn("read", read, skrip.RequireCapability(object.FS_CAPABILITY)),
+						skrip.WithMethod(object.STRING_OBJECT, "read", read, skrip.RequireCapability(object.FS_CAPABILITY)),
+					)
+
+					interpreter.RegisterBuiltIn("fetch", read, skrip.RequireCapability(object.NET_CAPABILITY))
+					interpreter.RegisterFunc("now", func() int { return 1 }, skrip.RequireCapability(object.TIME_CAPABILITY))
+					interpreter.RegisterFunc("one", func() int { return 1 })
+
+					expecteds := []struct {
+						source     string
+						capability string
+					}{
+						{`read()`, "fs"},
+						{`"a".read()`, "fs"},
+						{`fetch()`, "net"},
+						{`now()`, "time"},
+					}
+
+					for _, expected := range expecteds {
+						_, err := interpreter.Eval(expected.source)
+						So(err.Error(), ShouldEqual, "PermissionError: Permission denied, the "+expected.capability+" capability is not granted")
+					}
+
+					result, err := interpreter.Eval(`one()`)
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "1")
+
+					result, err = skrip.New(skrip.WithEngine(engine), skrip.WithBuiltIn("read", read, skrip.RequireCapability(object.FS_CAPABILITY))).Eval(`read()`)
+					So(err, ShouldBeNil)
+					So(result.Inspect(), ShouldEqual, "content")
+				})
+			})
+		}
+
+		Convey("Unknown engine", func() {
+			_, err := skrip.New(skrip.WithEngine("foo")).Eval(`1`)
+
+			So(err.Error(), ShouldEqual, "Unknown engine: foo")
+		})
+	})
+}
+
+func TestInterpreterLimits(t *testing.T) {
+	Convey("Interpreter limits test", t, func() {
+		for _, engine := range engines {
+			Convey(runMessage("Engine: %s", engine), func() {
+				interpreter := skrip.New(
+					skrip.WithEngine(engine),
+					skrip.WithLimits(object.Limits{MaxSteps: 1000, MaxCallDepth: 5, MaxAllocations: 10}),
+				)
+
+				Convey("Limit error", func() {
+					expecteds := []struct {
+						source string
+						kind   string
+					}{
+						{`for { }`, object.STEP_LIMIT_ERROR},
+						{`func f() { f() }; f()`, object.CALL_DEPTH_ERROR},
+						{`1..20`, object.ALLOCATION_LIMIT_ERROR},
+					}
+
+					for index, expected := range expecteds {
+						Convey(runMessage("Running: %d, Source: %s", index, expected.source), func() {
+							_, err := interpreter.Eval(expected.source)
+
+							So(err.(*skrip.RuntimeError).Err.Kind, ShouldEqual, expected.kind)
+						})
+					}
+				})
+
+				Convey("Each evaluation has its own limits", func() {
+					for index := 0; index < 3; index++ {
+						_, err := interpreter.Eval(`let a = 1..8; for i in a { }`)
+
+						So(err, ShouldBeNil)
+					}
+				})
+
+				Convey("Cancel by context", func() {
+					ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+					defer cancel()
+
+					_, err := skrip.New(skrip.WithEngine(engine)).EvalContext(ctx, `for { }`)
+
+					So(err.Error(), ShouldEqual, "CancelError: Execution cancelled: context deadline exceeded")
+				})
+			})
+		}
+	})
+}
+
+func runMessage(format string, values ...interface{}) string {
+	return fmt.Sprintf(format, values...)
+}
+
+func TestInterpreterConcurrency(t *testing.T) {
+	Convey("Interpreter concurrency test", t, func() {
+		directory, err := ioutil.TempDir("", "skrip")
+		So(err, ShouldBeNil)
+
+		defer os.RemoveAll(directory)
+
+		ioutil.WriteFile(filepath.Join(directory, "lib.sk"), []byte(`func fib(n) { if (n < 2) { return n }; fib(n - 1) + fib(n - 2) }`), 0644)
+		ioutil.WriteFile(filepath.Join(directory, "main.sk"), []byte(`import "lib.sk"; let total = lib.fib(15) + id(); println(total); total`), 0644)
+
+		const workers = 16
+
+		for _, engine := range engines {
+			Convey(runMessage("Engine: %s", engine), func() {
+				Convey("Independent interpreters run in parallel", func() {
+					var wait sync.WaitGroup
+
+					outputs := make([]string, workers)
+					results := make([]string, workers)
+					errs := make([]error, workers)
+
+					for index := 0; index < workers; index++ {
+						wait.Add(1)
+
+						go func(index int) {
+							defer wait.Done()
+
+							stdout := &bytes.Buffer{}
+
+							interpreter := skrip.New(
+								skrip.WithEngine(engine),
+								skrip.WithStdout(stdout),
+								skrip.WithCapabilities(object.IO_CAPABILITY, object.FS_CAPABILITY),
+								skrip.WithLimits(object.Limits{MaxSteps: 10000000}),
+							)
+							interpreter.RegisterFunc("id", func() int { return index })
+
+							result, err := interpreter.EvalFile(filepath.Join(directory, "main.sk"))
+							if err != nil {
+								errs[index] = err
+								return
+							}
+
+							outputs[index] = stdout.String()
+							results[index] = result.Inspect()
+						}(index)
+					}
+
+					wait.Wait()
+
+					for index := 0; index < workers; index++ {
+						So(errs[index], ShouldBeNil)
+						So(results[index], ShouldEqual, fmt.Sprint(610+index))
+						So(outputs[index], ShouldEqual, fmt.Sprintf("%d\n", 610+index))
+					}
+				})
+
+				Convey("Shared interpreter runs the evaluations one by one", func() {
+					var wait sync.WaitGroup
+
+					interpreter := skrip.New(skrip.WithEngine(engine))
+					interpreter.Set("counter", &object.Integer{Value: 0})
+
+					for index := 0; index < workers; index++ {
+						wait.Add(2)
+
+						go func() {
+							defer wait.Done()
+
+							interpreter.Eval(`let counter = counter + 1`)
+						}()
+
+						go func(index int) {
+							defer wait.Done()
+
+							interpreter.Set(fmt.Sprintf("value%d", index), &object.Integer{Value: int64(index)})
+							interpreter.Get("counter")
+						}(index)
+					}
+
+					wait.Wait()
+
+					counter, _ := interpreter.Get("counter")
+
+					So(counter.Inspect(), ShouldEqual, fmt.Sprint(workers))
+				})
+			})
+		}
+	})
+}
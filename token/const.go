@@ -11,6 +11,10 @@ const (
 	FLOAT      = "FLOAT"      // 12.345
 	STRING     = "STRING"     // "text"
 
+	TEMPLATE_HEAD   = "TEMPLATE_HEAD"   // "text ${
+	TEMPLATE_MIDDLE = "TEMPLATE_MIDDLE" // } text ${
+	TEMPLATE_TAIL   = "TEMPLATE_TAIL"   // } text"
+
 	// Operators
 	ASSIGN   = "="
 	PLUS     = "+"
@@ -29,8 +33,12 @@ const (
 	AND    = "&&"
 	OR     = "||"
 
-	DOT   = "."
-	RANGE = ".."
+	DOT      = "."
+	RANGE    = ".."
+	ELLIPSIS = "..."
+
+	ARROW = "=>"
+	PIPE  = "|"
 
 	// Delimiters
 	COMMA     = ","
@@ -57,4 +65,12 @@ const (
 	NIL      = "NIL"
 	BREAK    = "BREAK"
 	CONTINUE = "CONTINUE"
+	IMPORT   = "IMPORT"
+	AS       = "AS"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	THROW    = "THROW"
+	MATCH    = "MATCH"
+	STRUCT   = "STRUCT"
 )
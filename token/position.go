@@ -0,0 +1,27 @@
+package token
+
+import (
+	"fmt"
+)
+
+// Position is the location of source code
+type Position struct {
+	File   string // empty when the source is not loaded from file like eval mode
+	Line   int    // start from 1
+	Column int    // start from 1
+	Offset int    // byte offset from the beginning of source, start from 0
+}
+
+// IsValid returns the position is located or not
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the location like file:line:column or line:column when the file is empty
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
@@ -8,6 +8,20 @@ type Token struct {
 	Type       Type
 	Literal    string
 	LineNumber int
+	Column     int
+	Offset     int
+	File       string
+	End        Position // the position after the last character of token
+}
+
+// Position returns the start position of token
+func (t Token) Position() Position {
+	return Position{
+		File:   t.File,
+		Line:   t.LineNumber,
+		Column: t.Column,
+		Offset: t.Offset,
+	}
 }
 
 var keywords = map[string]Type{
@@ -23,6 +37,14 @@ var keywords = map[string]Type{
 	"nil":      NIL,
 	"break":    BREAK,
 	"continue": CONTINUE,
+	"import":   IMPORT,
+	"as":       AS,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
+	"throw":    THROW,
+	"match":    MATCH,
+	"struct":   STRUCT,
 }
 
 // FindKeywordType will return keyword type
@@ -0,0 +1,32 @@
+package vm
+
+import (
+	"github.com/zeuxisoo/go-skrip/compiler"
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+// Frame is the call frame of function, the main program is also running in a frame
+type Frame struct {
+	closure     *object.Function
+	ip          int
+	basePointer int
+	callee      string // the function name in call expression for the call stack of error
+}
+
+func NewFrame(closure *object.Function, basePointer int, callee string) *Frame {
+	return &Frame{
+		closure:     closure,
+		ip:          -1,
+		basePointer: basePointer,
+		callee:      callee,
+	}
+}
+
+func (f *Frame) Instructions() compiler.Instructions {
+	return f.closure.Compiled.Instructions
+}
+
+// Constants returns the constant pool of the program which compiled the running function
+func (f *Frame) Constants() []object.Object {
+	return f.closure.Compiled.Constants
+}
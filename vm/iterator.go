@@ -0,0 +1,72 @@
+package vm
+
+import (
+	"github.com/zeuxisoo/go-skrip/object"
+)
+
+const ITERATOR_OBJECT = "ITERATOR_OBJECT"
+
+// iterator keeps the loop position of for each expression on the stack
+type iterator struct {
+	keys   []object.Object
+	values []object.Object
+	index  int
+}
+
+func (i *iterator) Type() object.ObjectType {
+	return ITERATOR_OBJECT
+}
+
+func (i *iterator) Inspect() string {
+	return "iterator"
+}
+
+func (i *iterator) next() (object.Object, object.Object, bool) {
+	if i.index >= len(i.values) {
+		return nil, nil, false
+	}
+
+	var key object.Object
+
+	// The key of array is the index of element
+	if i.keys != nil {
+		key = i.keys[i.index]
+	} else {
+		key = &object.Integer{Value: int64(i.index)}
+	}
+
+	value := i.values[i.index]
+
+	i.index++
+
+	return key, value, true
+}
+
+func newArrayIterator(array *object.Array) *iterator {
+	return &iterator{
+		values: array.Elements,
+	}
+}
+
+func newStringIterator(str *object.String) *iterator {
+	return &iterator{
+		values: str.Chars(),
+	}
+}
+
+func newHashIterator(hash *object.Hash) *iterator {
+	keys := make([]object.Object, 0, len(hash.Order))
+	values := make([]object.Object, 0, len(hash.Order))
+
+	for _, hashKey := range hash.Order {
+		pair := hash.Pairs[hashKey]
+
+		keys = append(keys, pair.Key)
+		values = append(values, pair.Value)
+	}
+
+	return &iterator{
+		keys:   keys,
+		values: values,
+	}
+}
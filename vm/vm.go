@@ -0,0 +1,827 @@
+package vm
+
+import (
+	"github.com/zeuxisoo/go-skrip/ast"
+	"github.com/zeuxisoo/go-skrip/compiler"
+	"github.com/zeuxisoo/go-skrip/evaluator"
+	"github.com/zeuxisoo/go-skrip/object"
+	"github.com/zeuxisoo/go-skrip/token"
+)
+
+const (
+	StackSize   = 2048 // the initial size of stack, it grows with the call depth
+	GlobalsSize = 65536
+	FramesSize  = 1024 // the initial size of frames, it grows with the call depth
+)
+
+type VM struct {
+	environment *object.Environment
+
+	globals     []object.Object
+	globalNames map[string]int
+
+	stack []object.Object
+	sp    int // stack pointer, the top of stack is stack[sp-1]
+
+	frames      []*Frame
+	framesIndex int
+
+	builtInCallee string // the name of running built-in function, it is the callee of function which called back by it
+}
+
+// State keeps the symbols and global variables of vm between evaluations in the same environment
+type State struct {
+	SymbolTable *compiler.SymbolTable
+	Globals     []object.Object
+}
+
+func NewState() *State {
+	return &State{
+		SymbolTable: compiler.NewSymbolTable(),
+		Globals:     make([]object.Object, GlobalsSize),
+	}
+}
+
+// Eval compiles the node to bytecode and runs it in vm,
+// the whole program will be evaluated by evaluator when any node of it cannot be compiled yet (e.g. import)
+func Eval(node ast.Node, env *object.Environment) object.Object {
+	result, err := EvalBytecode(node, env)
+	if _, ok := err.(*compiler.UnsupportedError); ok {
+		return evaluator.Eval(node, env)
+	}
+
+	return result
+}
+
+// EvalBytecode is the same as Eval without the fallback, it returns the UnsupportedError when the node cannot be compiled
+func EvalBytecode(node ast.Node, env *object.Environment) (object.Object, error) {
+	state := loadState(env)
+	theCompiler := compiler.NewCompilerWithState(state.SymbolTable)
+
+	if err := theCompiler.Compile(node); err != nil {
+		if _, ok := err.(*compiler.UnsupportedError); ok {
+			return nil, err
+		}
+
+		return newError("Cannot compile: %s", err), nil
+	}
+
+	bytecode := theCompiler.Bytecode()
+
+	theVM := NewVMWithGlobals(bytecode, env, state.Globals)
+
+	// Sync the global variables with environment, they may be changed by evaluator or the environment owner
+	theVM.loadGlobals()
+	result := theVM.Run()
+	theVM.storeGlobals()
+
+	return result, nil
+}
+
+// The state is kept in environment, so the functions and variables can be used in next evaluation
+func loadState(env *object.Environment) *State {
+	if state, ok := env.EngineState().(*State); ok {
+		return state
+	}
+
+	state := NewState()
+	env.SetEngineState(state)
+
+	return state
+}
+
+func NewVM(bytecode *compiler.Bytecode, env *object.Environment) *VM {
+	return NewVMWithGlobals(bytecode, env, make([]object.Object, GlobalsSize))
+}
+
+func NewVMWithGlobals(bytecode *compiler.Bytecode, env *object.Environment, globals []object.Object) *VM {
+	mainFunction := &object.CompiledFunction{
+		Instructions: bytecode.Instructions,
+		Positions:    bytecode.Positions,
+		Constants:    bytecode.Constants,
+	}
+
+	mainClosure := &object.Function{
+		Compiled: mainFunction,
+	}
+
+	frames := make([]*Frame, FramesSize)
+	frames[0] = NewFrame(mainClosure, 0, "")
+
+	return &VM{
+		environment: env,
+
+		globals:     globals,
+		globalNames: bytecode.Globals,
+
+		stack: make([]object.Object, StackSize),
+		sp:    0,
+
+		frames:      frames,
+		framesIndex: 1,
+	}
+}
+
+// Run executes the instructions and returns the value of last statement like evaluator
+func (vm *VM) Run() object.Object {
+	// The built-in function calls back the function by this vm, restore the caller for the engine which run this program
+	previousCaller := vm.environment.Caller()
+	vm.environment.SetCaller(vm.callBack)
+	defer vm.environment.SetCaller(previousCaller)
+
+	return vm.run(0)
+}
+
+// Execute the instructions until the frames returned to the base frames, the main frame is never returned
+func (vm *VM) run(baseFrames int) object.Object {
+	for vm.currentFrame().ip < len(vm.currentFrame().Instructions())-1 {
+		vm.currentFrame().ip++
+
+		// Stop the program when it is cancelled or the steps exceeded
+		if err := vm.environment.Step(); err != nil {
+			vm.locateError(err)
+
+			return vm.unwind(err)
+		}
+
+		frame := vm.currentFrame()
+		instructions := frame.Instructions()
+		ip := frame.ip
+		op := compiler.Opcode(instructions[ip])
+
+		var err *object.Error
+
+		switch op {
+		case compiler.OpConstant:
+			constantIndex := compiler.ReadUint16(instructions[ip+1:])
+			frame.ip += 2
+
+			err = vm.push(frame.Constants()[constantIndex])
+		case compiler.OpPop:
+			vm.pop()
+		case compiler.OpTrue:
+			err = vm.push(evaluator.TRUE)
+		case compiler.OpFalse:
+			err = vm.push(evaluator.FALSE)
+		case compiler.OpNil:
+			err = vm.push(evaluator.NIL)
+
+		case compiler.OpInfix:
+			operator := compiler.InfixOperator(int(compiler.ReadUint8(instructions[ip+1:])))
+			frame.ip++
+
+			right := vm.pop()
+			left := vm.pop()
+
+			err = vm.pushResult(evaluator.InfixOperator(left, operator, right, vm.environment))
+		case compiler.OpPrefix:
+			operator := compiler.PrefixOperator(int(compiler.ReadUint8(instructions[ip+1:])))
+			frame.ip++
+
+			err = vm.pushResult(evaluator.PrefixOperator(operator, vm.pop()))
+
+		case compiler.OpJump:
+			position := int(compiler.ReadUint16(instructions[ip+1:]))
+			frame.ip = position - 1
+		case compiler.OpJumpNotTruthy:
+			position := int(compiler.ReadUint16(instructions[ip+1:]))
+			frame.ip += 2
+
+			if evaluator.IsTruthy(vm.pop()) == false {
+				frame.ip = position - 1
+			}
+		case compiler.OpJumpTruthyOrPop, compiler.OpJumpNotTruthyOrPop:
+			position := int(compiler.ReadUint16(instructions[ip+1:]))
+			frame.ip += 2
+
+			if evaluator.IsTruthy(vm.stack[vm.sp-1]) == (op == compiler.OpJumpTruthyOrPop) {
+				frame.ip = position - 1
+			} else {
+				vm.pop()
+			}
+		case compiler.OpGetGlobal:
+			globalIndex := compiler.ReadUint16(instructions[ip+1:])
+			frame.ip += 2
+
+			err = vm.pushGlobal(int(globalIndex))
+		case compiler.OpSetGlobal:
+			globalIndex := compiler.ReadUint16(instructions[ip+1:])
+			frame.ip += 2
+
+			vm.globals[globalIndex] = vm.pop()
+		case compiler.OpGetLocal:
+			localIndex := compiler.ReadUint8(instructions[ip+1:])
+			frame.ip++
+
+			err = vm.pushLocal(frame, int(localIndex))
+		case compiler.OpSetLocal:
+			localIndex := compiler.ReadUint8(instructions[ip+1:])
+			frame.ip++
+
+			vm.stack[frame.basePointer+int(localIndex)] = vm.pop()
+		case compiler.OpGetFree:
+			freeIndex := compiler.ReadUint8(instructions[ip+1:])
+			frame.ip++
+
+			err = vm.pushFree(frame, int(freeIndex))
+		case compiler.OpCurrentClosure:
+			err = vm.push(frame.closure)
+		case compiler.OpGetName:
+			constantIndex := compiler.ReadUint16(instructions[ip+1:])
+			frame.ip += 2
+
+			err = vm.pushName(frame.Constants()[constantIndex].(*object.String).Value)
+		case compiler.OpSetName:
+			constantIndex := compiler.ReadUint16(instructions[ip+1:])
+			frame.ip += 2
+
+			vm.environment.Set(frame.Constants()[constantIndex].(*object.String).Value, vm.pop())
+
+		case compiler.OpArray:
+			length := int(compiler.ReadUint16(instructions[ip+1:]))
+			frame.ip += 2
+
+			err = vm.pushArray(length)
+		case compiler.OpTemplate:
+			length := int(compiler.ReadUint16(instructions[ip+1:]))
+			frame.ip += 2
+
+			parts := make([]object.Object, length)
+			copy(parts, vm.stack[vm.sp-length:vm.sp])
+			vm.sp = vm.sp - length
+
+			err = vm.pushResult(evaluator.TemplateOperator(parts))
+		case compiler.OpHash:
+			length := int(compiler.ReadUint16(instructions[ip+1:]))
+			frame.ip += 2
+
+			err = vm.pushHash(length)
+		case compiler.OpIndex:
+			index := vm.pop()
+			left := vm.pop()
+
+			err = vm.pushResult(evaluator.IndexOperator(left, index))
+		case compiler.OpDot:
+			item := vm.pop()
+			left := vm.pop()
+
+			err = vm.pushResult(evaluator.DotOperator(left, item, vm.environment))
+		case compiler.OpRange:
+			end := vm.pop()
+			start := vm.pop()
+
+			err = vm.pushResult(evaluator.RangeOperator(start, end, vm.environment))
+		case compiler.OpSetIndex:
+			index := vm.pop()
+			left := vm.pop()
+			value := vm.pop()
+
+			err = vm.pushResult(evaluator.AssignIndexOperator(left, index, value))
+		case compiler.OpSetDot:
+			item := vm.pop()
+			left := vm.pop()
+			value := vm.pop()
+
+			err = vm.pushResult(evaluator.AssignDotOperator(left, item, value))
+
+		case compiler.OpCall:
+			numArguments := int(compiler.ReadUint8(instructions[ip+1:]))
+			calleeIndex := compiler.ReadUint16(instructions[ip+2:])
+			frame.ip += 3
+
+			err = vm.callFunction(numArguments, frame.Constants()[calleeIndex].(*object.String).Value)
+		case compiler.OpReturnValue:
+			returnValue := vm.pop()
+
+			// Return in main program will stop the program like evaluator
+			if vm.framesIndex == 1 {
+				return returnValue
+			}
+
+			vm.returnFunction(returnValue)
+
+			if vm.framesIndex == baseFrames {
+				return vm.pop()
+			}
+		case compiler.OpReturn:
+			if vm.framesIndex == 1 {
+				return evaluator.NIL
+			}
+
+			vm.returnFunction(evaluator.NIL)
+
+			if vm.framesIndex == baseFrames {
+				return vm.pop()
+			}
+		case compiler.OpClosure:
+			constantIndex := compiler.ReadUint16(instructions[ip+1:])
+			numFree := int(compiler.ReadUint8(instructions[ip+3:]))
+			frame.ip += 3
+
+			err = vm.pushClosure(int(constantIndex), numFree)
+
+		case compiler.OpIterator:
+			kind := int(compiler.ReadUint8(instructions[ip+1:]))
+			frame.ip++
+
+			err = vm.pushIterator(kind, vm.pop())
+		case compiler.OpIterNext:
+			position := int(compiler.ReadUint16(instructions[ip+1:]))
+			frame.ip += 2
+
+			theIterator := vm.stack[vm.sp-1].(*iterator)
+
+			key, value, ok := theIterator.next()
+			if ok == false {
+				vm.pop()
+
+				frame.ip = position - 1
+				break
+			}
+
+			err = vm.push(key)
+
+			if err == nil {
+				err = vm.push(value)
+			}
+		default:
+			err = newError("Unknown opcode %d", op)
+		}
+
+		if err != nil {
+			vm.locateError(err)
+
+			return vm.unwind(err)
+		}
+	}
+
+	return vm.LastPoppedStackElement()
+}
+
+// LastPoppedStackElement returns the value of last expression statement
+func (vm *VM) LastPoppedStackElement() object.Object {
+	return vm.stack[vm.sp]
+}
+
+// Stack functions
+func (vm *VM) push(obj object.Object) *object.Error {
+	vm.growStack(vm.sp + 1)
+
+	vm.stack[vm.sp] = obj
+	vm.sp++
+
+	return nil
+}
+
+// Grow the stack to fit the size, so the recursion is limited by the call depth instead of stack size
+func (vm *VM) growStack(size int) {
+	if size <= len(vm.stack) {
+		return
+	}
+
+	capacity := len(vm.stack) * 2
+	for capacity < size {
+		capacity *= 2
+	}
+
+	stack := make([]object.Object, capacity)
+	copy(stack, vm.stack)
+
+	vm.stack = stack
+}
+
+func (vm *VM) pop() object.Object {
+	obj := vm.stack[vm.sp-1]
+	vm.sp--
+
+	return obj
+}
+
+// Push the result of operator, the error result will be returned to stop vm
+func (vm *VM) pushResult(obj object.Object) *object.Error {
+	if err, ok := obj.(*object.Error); ok {
+		return err
+	}
+
+	return vm.push(obj)
+}
+
+func (vm *VM) pushGlobal(index int) *object.Error {
+	value := vm.globals[index]
+
+	// The global variable was defined in compile time but still not set, e.g. let in the not executed block
+	if value == nil {
+		for name, globalIndex := range vm.globalNames {
+			if globalIndex == index {
+				return vm.pushName(name)
+			}
+		}
+
+		return newError("Global variable not found: %d", index)
+	}
+
+	return vm.push(value)
+}
+
+func (vm *VM) pushLocal(frame *Frame, index int) *object.Error {
+	value := vm.stack[frame.basePointer+index]
+
+	// The local variable was defined in compile time but still not set, resolve it from outer scope like evaluator
+	if value == nil {
+		return vm.pushName(frame.closure.Compiled.LocalNames[index])
+	}
+
+	return vm.push(value)
+}
+
+func (vm *VM) pushFree(frame *Frame, index int) *object.Error {
+	value := frame.closure.Free[index]
+
+	// The captured variable was not set when the closure created
+	if value == nil {
+		return vm.pushName(frame.closure.Compiled.FreeNames[index])
+	}
+
+	return vm.push(value)
+}
+
+func (vm *VM) loadGlobals() {
+	for name, index := range vm.globalNames {
+		if value, ok := vm.environment.Get(name); ok {
+			vm.globals[index] = value
+		}
+	}
+}
+
+func (vm *VM) storeGlobals() {
+	for name, index := range vm.globalNames {
+		if vm.globals[index] != nil {
+			vm.environment.Set(name, vm.globals[index])
+		}
+	}
+}
+
+func (vm *VM) pushName(name string) *object.Error {
+	// Defined after the function compiled, e.g. func a() { b() }; func b() {}
+	if index, ok := vm.globalNames[name]; ok && vm.globals[index] != nil {
+		return vm.push(vm.globals[index])
+	}
+
+	if value, ok := vm.environment.Get(name); ok {
+		return vm.push(value)
+	}
+
+	if builtIn, ok := evaluator.FindBuiltIn(name, vm.environment); ok {
+		return vm.push(builtIn)
+	}
+
+	return newError("Identifier not found: " + name)
+}
+
+func (vm *VM) pushArray(length int) *object.Error {
+	if err := vm.environment.Allocate(int64(length)); err != nil {
+		return err
+	}
+
+	elements := make([]object.Object, length)
+	copy(elements, vm.stack[vm.sp-length:vm.sp])
+
+	vm.sp = vm.sp - length
+
+	return vm.push(&object.Array{
+		Elements: elements,
+	})
+}
+
+func (vm *VM) pushHash(length int) *object.Error {
+	if err := vm.environment.Allocate(int64(length)); err != nil {
+		return err
+	}
+
+	hashObject := &object.Hash{
+		Order: []object.HashKey{},
+		Pairs: make(map[object.HashKey]object.HashPair),
+	}
+
+	start := vm.sp - length*2
+
+	for index := start; index < vm.sp; index += 2 {
+		key := vm.stack[index]
+		value := vm.stack[index+1]
+
+		hashableKey, ok := key.(object.Hashable)
+		if ok == false {
+			return newError("Cannot use %s as hash key", key.Type())
+		}
+
+		hashedKey := hashableKey.HashKey()
+
+		if _, exists := hashObject.Pairs[hashedKey]; exists == false {
+			hashObject.Order = append(hashObject.Order, hashedKey)
+		}
+
+		hashObject.Pairs[hashedKey] = object.HashPair{
+			Key:   key,
+			Value: value,
+		}
+	}
+
+	vm.sp = start
+
+	return vm.push(hashObject)
+}
+
+func (vm *VM) pushClosure(constantIndex int, numFree int) *object.Error {
+	constant := vm.currentFrame().Constants()[constantIndex]
+
+	compiledFunction, ok := constant.(*object.CompiledFunction)
+	if ok == false {
+		return newError("%s is not a function", constant.Type())
+	}
+
+	free := make([]object.Object, numFree)
+	copy(free, vm.stack[vm.sp-numFree:vm.sp])
+
+	vm.sp = vm.sp - numFree
+
+	// Keep the environment for the function which may be called by evaluator in next evaluation
+	return vm.push(&object.Function{
+		Parameters:  compiledFunction.Parameters,
+		Block:       compiledFunction.Block,
+		Environment: vm.environment,
+		Compiled:    compiledFunction,
+		Free:        free,
+	})
+}
+
+func (vm *VM) pushIterator(kind int, iterable object.Object) *object.Error {
+	if _, ok := iterable.(object.Iterable); ok == false {
+		return newError("%s is not iterable", iterable.Inspect())
+	}
+
+	switch iter := iterable.(type) {
+	case *object.Array:
+		if kind == compiler.IteratorArray {
+			return vm.push(newArrayIterator(iter))
+		}
+	case *object.String:
+		if kind == compiler.IteratorArray {
+			return vm.push(newStringIterator(iter))
+		}
+	case *object.Hash:
+		if kind == compiler.IteratorHash {
+			return vm.push(newHashIterator(iter))
+		}
+	}
+
+	return newError("%s is a %s, not support for loop", iterable.Inspect(), iterable.Type())
+}
+
+// Frame functions
+func (vm *VM) currentFrame() *Frame {
+	return vm.frames[vm.framesIndex-1]
+}
+
+func (vm *VM) pushFrame(frame *Frame) *object.Error {
+	// The main frame is not counted in call depth
+	if vm.framesIndex > vm.environment.MaxCallDepth() {
+		return vm.environment.CallDepthError()
+	}
+
+	if vm.framesIndex >= len(vm.frames) {
+		vm.frames = append(vm.frames, frame)
+	} else {
+		vm.frames[vm.framesIndex] = frame
+	}
+
+	vm.framesIndex++
+
+	return nil
+}
+
+func (vm *VM) popFrame() *Frame {
+	vm.framesIndex--
+
+	return vm.frames[vm.framesIndex]
+}
+
+func (vm *VM) callFunction(numArguments int, callee string) *object.Error {
+	function := vm.stack[vm.sp-1-numArguments]
+
+	switch fn := function.(type) {
+	// custom function
+	case *object.Function:
+		// The function is created by evaluator in the previous evaluation which fell back to evaluator
+		if fn.Compiled == nil {
+			return vm.callByEvaluator(fn, numArguments, callee)
+		}
+
+		if numArguments != fn.Compiled.NumParameters {
+			arguments := make([]object.Object, numArguments)
+			copy(arguments, vm.stack[vm.sp-numArguments:vm.sp])
+
+			return vm.callError(callee, argumentsError(fn, arguments))
+		}
+
+		return vm.enterFunction(fn, numArguments, callee)
+	// built-in function
+	case *object.BuiltIn:
+		arguments := make([]object.Object, numArguments)
+		copy(arguments, vm.stack[vm.sp-numArguments:vm.sp])
+
+		return vm.callBuiltIn(fn, arguments, numArguments, callee)
+	// method of built-in type, e.g. "abc".upper()
+	case *object.BoundMethod:
+		arguments := make([]object.Object, numArguments+1)
+		arguments[0] = fn.Receiver
+		copy(arguments[1:], vm.stack[vm.sp-numArguments:vm.sp])
+
+		return vm.callBuiltIn(fn.Method, arguments, numArguments, callee)
+	// struct, it is defined by evaluator only
+	case *object.Struct:
+		return vm.callByEvaluator(fn, numArguments, callee)
+	default:
+		return vm.callError(callee, newError("%s is not a function", fn.Type()))
+	}
+}
+
+// Call the built-in function and replace the function and its arguments in stack by the result,
+// the arguments may contain the receiver of method which is not in stack
+func (vm *VM) callBuiltIn(fn *object.BuiltIn, arguments []object.Object, numArguments int, callee string) *object.Error {
+	if err := vm.environment.CheckPermission(fn); err != nil {
+		return vm.callError(callee, err)
+	}
+
+	previousCallee := vm.builtInCallee
+	vm.builtInCallee = callee
+
+	result := fn.Function(vm.environment, arguments...)
+
+	vm.builtInCallee = previousCallee
+
+	vm.sp = vm.sp - numArguments - 1
+
+	if err, ok := result.(*object.Error); ok {
+		return vm.callError(callee, err)
+	}
+
+	if result == nil {
+		result = evaluator.NIL
+	}
+
+	return vm.push(result)
+}
+
+// Call the function or struct which cannot be run in vm by evaluator, and replace it and its arguments in stack by the result
+func (vm *VM) callByEvaluator(function object.Object, numArguments int, callee string) *object.Error {
+	arguments := make([]object.Object, numArguments)
+	copy(arguments, vm.stack[vm.sp-numArguments:vm.sp])
+
+	result := evaluator.CallFunction(vm.environment, function, arguments)
+
+	vm.sp = vm.sp - numArguments - 1
+
+	if err, ok := result.(*object.Error); ok {
+		return vm.callError(callee, err)
+	}
+
+	if result == nil {
+		result = evaluator.NIL
+	}
+
+	return vm.push(result)
+}
+
+// Push the frame of function which arguments are pushed to stack already, and reserve the slots for its locals
+func (vm *VM) enterFunction(fn *object.Function, numArguments int, callee string) *object.Error {
+	basePointer := vm.sp - numArguments
+
+	if err := vm.pushFrame(NewFrame(fn, basePointer, callee)); err != nil {
+		return err
+	}
+
+	vm.growStack(basePointer + fn.Compiled.NumLocals)
+
+	// Clean the local slots which may be used by previous call
+	for index := vm.sp; index < basePointer+fn.Compiled.NumLocals; index++ {
+		vm.stack[index] = nil
+	}
+
+	vm.sp = basePointer + fn.Compiled.NumLocals
+
+	return nil
+}
+
+// The function caller of environment, it runs the compiled function which called back by built-in function
+// in a nested loop, the other functions are called by evaluator
+func (vm *VM) callBack(env *object.Environment, function object.Object, arguments []object.Object) object.Object {
+	fn, ok := function.(*object.Function)
+	if ok == false || fn.Compiled == nil {
+		return evaluator.CallFunction(env, function, arguments)
+	}
+
+	if len(arguments) != fn.Compiled.NumParameters {
+		return argumentsError(fn, arguments)
+	}
+
+	sp, framesIndex := vm.sp, vm.framesIndex
+
+	vm.push(fn)
+	for _, argument := range arguments {
+		vm.push(argument)
+	}
+
+	if err := vm.enterFunction(fn, len(arguments), vm.builtInCallee); err != nil {
+		vm.sp = sp
+
+		return err
+	}
+
+	result := vm.run(framesIndex)
+
+	// The error is returned to the built-in function with the call stack, restore the frames of its caller
+	if _, ok := result.(*object.Error); ok {
+		vm.sp, vm.framesIndex = sp, framesIndex
+	}
+
+	return result
+}
+
+func (vm *VM) returnFunction(returnValue object.Object) {
+	frame := vm.popFrame()
+
+	// Remove the arguments, locals and the function itself
+	vm.sp = frame.basePointer - 1
+
+	vm.push(returnValue)
+}
+
+// Return the error to main frame with the call stack
+func (vm *VM) unwind(err *object.Error) object.Object {
+	if err.Stack == nil {
+		err.Stack = vm.callStack()
+	}
+
+	vm.framesIndex = 1
+
+	return err
+}
+
+// Locate the error and keep the call stack with the function which cannot be called like evaluator
+func (vm *VM) callError(callee string, err *object.Error) *object.Error {
+	vm.locateError(err)
+
+	if err.Stack == nil {
+		frame := object.StackFrame{
+			Function: callee,
+			Position: vm.currentPosition(vm.currentFrame()),
+		}
+
+		err.Stack = append([]object.StackFrame{frame}, vm.callStack()...)
+	}
+
+	return err
+}
+
+// Collect the calling functions from the innermost frame, each function is called at the position of its caller
+func (vm *VM) callStack() []object.StackFrame {
+	frames := make([]object.StackFrame, 0, vm.framesIndex-1)
+
+	for index := vm.framesIndex - 1; index > 0; index-- {
+		frames = append(frames, object.StackFrame{
+			Function: vm.frames[index].callee,
+			Position: vm.currentPosition(vm.frames[index-1]),
+		})
+	}
+
+	return frames
+}
+
+func (vm *VM) currentPosition(frame *Frame) token.Position {
+	return frame.closure.Compiled.PositionAt(frame.ip)
+}
+
+// Set the source position of current instruction to error if it is not located
+func (vm *VM) locateError(err *object.Error) {
+	if err.Position.IsValid() == true {
+		return
+	}
+
+	err.Position = vm.currentPosition(vm.currentFrame())
+}
+
+// Helper functions
+func argumentsError(fn *object.Function, arguments []object.Object) *object.Error {
+	if len(arguments) > len(fn.Parameters) {
+		return newError(
+			"too many arguments for %s, Got: %d, Expected: %d",
+			fn.Signature(), len(arguments), len(fn.Parameters),
+		)
+	}
+
+	return newError("missing argument %s for %s", fn.Parameters[len(arguments)].Value, fn.Signature())
+}
+
+func newError(format string, values ...interface{}) *object.Error {
+	return object.NewError(object.RUNTIME_ERROR, format, values...)
+}